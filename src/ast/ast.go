@@ -40,6 +40,136 @@ func (ls *LetStatement) String() string {
 	return out.String()
 }
 
+// Node of a destructuring let, it binds the elements of an array to Names in
+// order, as in `let [q, r] = divmod(17, 5);`
+type DestructuringStatement struct {
+	Token token.Token // The LET token
+	Names []*Identifier
+	Value Expression
+}
+
+func (ds *DestructuringStatement) statementNode()       {}
+func (ds *DestructuringStatement) TokenLiteral() string { return ds.Token.Literal }
+func (ds *DestructuringStatement) String() string {
+	var out bytes.Buffer
+
+	names := []string{}
+	for _, name := range ds.Names {
+		names = append(names, name.String())
+	}
+
+	out.WriteString(ds.TokenLiteral() + " [")
+	out.WriteString(strings.Join(names, ", "))
+	out.WriteString("] = ")
+
+	if ds.Value != nil {
+		out.WriteString(ds.Value.String())
+	}
+	out.WriteString(";")
+	return out.String()
+}
+
+// Node of `struct Name { field, ... fn method(params) { ... } }`, it binds
+// Name to a constructor taking the fields in order
+type StructStatement struct {
+	Token   token.Token // The STRUCT token
+	Name    *Identifier
+	Fields  []*Identifier
+	Methods []*StructMethod
+}
+
+// A method declared in a struct, its body sees the instance as self
+type StructMethod struct {
+	Name     *Identifier
+	Function *FunctionLiteral
+}
+
+func (ss *StructStatement) statementNode()       {}
+func (ss *StructStatement) TokenLiteral() string { return ss.Token.Literal }
+func (ss *StructStatement) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, field := range ss.Fields {
+		fields = append(fields, field.String())
+	}
+
+	out.WriteString(ss.TokenLiteral() + " " + ss.Name.String() + " { ")
+	out.WriteString(strings.Join(fields, ", "))
+	for _, method := range ss.Methods {
+		params := []string{}
+		for _, p := range method.Function.Parameters {
+			params = append(params, p.String())
+		}
+		out.WriteString(" fn " + method.Name.String() + "(" + strings.Join(params, ", ") + ") ")
+		out.WriteString(method.Function.Body.String())
+	}
+	out.WriteString(" }")
+	return out.String()
+}
+
+// Node of a parallel let, it binds each of Names to the value at the same
+// position, as in `let a, b = 1, 2;`
+type ParallelLetStatement struct {
+	Token  token.Token // The LET token
+	Names  []*Identifier
+	Values []Expression
+}
+
+func (pl *ParallelLetStatement) statementNode()       {}
+func (pl *ParallelLetStatement) TokenLiteral() string { return pl.Token.Literal }
+func (pl *ParallelLetStatement) String() string {
+	return pl.TokenLiteral() + " " + parallelString(pl.Names, pl.Values) + ";"
+}
+
+// Node of `a, b = b, a;`, it rebinds existing variables once all values are
+// evaluated, so the values may refer to the old bindings
+type ParallelAssignStatement struct {
+	Token  token.Token // The = token
+	Names  []*Identifier
+	Values []Expression
+}
+
+func (pa *ParallelAssignStatement) statementNode()       {}
+func (pa *ParallelAssignStatement) TokenLiteral() string { return pa.Token.Literal }
+func (pa *ParallelAssignStatement) String() string {
+	return parallelString(pa.Names, pa.Values) + ";"
+}
+
+func parallelString(names []*Identifier, values []Expression) string {
+	targets := []string{}
+	for _, name := range names {
+		targets = append(targets, name.String())
+	}
+	exprs := []string{}
+	for _, value := range values {
+		exprs = append(exprs, value.String())
+	}
+	return strings.Join(targets, ", ") + " = " + strings.Join(exprs, ", ")
+}
+
+// Node of a global statement, it binds Name in the outermost environment
+type GlobalStatement struct {
+	Token token.Token // The GLOBAL token
+	Name  *Identifier
+	Value Expression
+}
+
+func (gs *GlobalStatement) statementNode()       {}
+func (gs *GlobalStatement) TokenLiteral() string { return gs.Token.Literal }
+func (gs *GlobalStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString(gs.TokenLiteral() + " ")
+	out.WriteString(gs.Name.String())
+	out.WriteString(" = ")
+
+	if gs.Value != nil {
+		out.WriteString(gs.Value.String())
+	}
+	out.WriteString(";")
+	return out.String()
+}
+
 type ReturnStatement struct {
 	Token       token.Token
 	ReturnValue Expression
@@ -59,6 +189,43 @@ func (rs *ReturnStatement) String() string {
 	return out.String()
 }
 
+// Node of `break`, it stops the innermost loop
+type BreakStatement struct {
+	Token token.Token // The BREAK token
+}
+
+func (bs *BreakStatement) statementNode()       {}
+func (bs *BreakStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BreakStatement) String() string       { return bs.TokenLiteral() + ";" }
+
+// Node of `continue`, it skips to the next iteration of the innermost loop
+type ContinueStatement struct {
+	Token token.Token // The CONTINUE token
+}
+
+func (cs *ContinueStatement) statementNode()       {}
+func (cs *ContinueStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ContinueStatement) String() string       { return cs.TokenLiteral() + ";" }
+
+// Node of `defer expr;`, it schedules Expression to be evaluated when the
+// enclosing function returns
+type DeferStatement struct {
+	Token      token.Token // The DEFER token
+	Expression Expression
+}
+
+func (ds *DeferStatement) statementNode()       {}
+func (ds *DeferStatement) TokenLiteral() string { return ds.Token.Literal }
+func (ds *DeferStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString(ds.TokenLiteral() + " ")
+	if ds.Expression != nil {
+		out.WriteString(ds.Expression.String())
+	}
+	out.WriteString(";")
+	return out.String()
+}
+
 // Node of an identifier
 type Identifier struct {
 	Token token.Token // The IDEN token
@@ -98,6 +265,15 @@ func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
 func (il *IntegerLiteral) String() string       { return il.Token.Literal }
 func (il *IntegerLiteral) expressionNode()      {}
 
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
+func (fl *FloatLiteral) expressionNode()      {}
+
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -116,6 +292,19 @@ func (pe *PrefixExpression) String() string {
 	return out.String()
 }
 
+// Node of `x++` and `x--`, they update the binding and yield its old value
+type PostfixExpression struct {
+	Token    token.Token // The ++ or -- token
+	Name     *Identifier
+	Operator string
+}
+
+func (pe *PostfixExpression) expressionNode()      {}
+func (pe *PostfixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PostfixExpression) String() string {
+	return "(" + pe.Name.String() + pe.Operator + ")"
+}
+
 type InfixExpression struct {
 	Token    token.Token
 	Left     Expression
@@ -145,13 +334,36 @@ func (bl *Boolean) expressionNode()      {}
 func (bl *Boolean) TokenLiteral() string { return bl.Token.Literal }
 func (bl *Boolean) String() string       { return bl.Token.Literal }
 
+type NullLiteral struct {
+	Token token.Token
+}
+
+func (nl *NullLiteral) expressionNode()      {}
+func (nl *NullLiteral) TokenLiteral() string { return nl.Token.Literal }
+func (nl *NullLiteral) String() string       { return nl.Token.Literal }
+
+// ElseIfs holds the `else if` branches in order, so a chain stays flat
+// instead of nesting an if expression in every alternative.
 type IfExpression struct {
 	Token       token.Token
 	Condition   Expression
 	Consequence *BlockStatement
+	ElseIfs     []*ElseIfBranch
 	Alternative *BlockStatement
 }
 
+// Node of an `else if (condition) { ... }` branch of an if expression
+type ElseIfBranch struct {
+	Token       token.Token // The IF token after else
+	Condition   Expression
+	Consequence *BlockStatement
+}
+
+func (eb *ElseIfBranch) TokenLiteral() string { return eb.Token.Literal }
+func (eb *ElseIfBranch) String() string {
+	return "else if" + eb.Condition.String() + " " + eb.Consequence.String()
+}
+
 func (ie *IfExpression) expressionNode()      {}
 func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
 func (ie *IfExpression) String() string {
@@ -161,6 +373,9 @@ func (ie *IfExpression) String() string {
 	out.WriteString(ie.Condition.String())
 	out.WriteString(" ")
 	out.WriteString(ie.Consequence.String())
+	for _, branch := range ie.ElseIfs {
+		out.WriteString(branch.String())
+	}
 	if ie.Alternative != nil {
 		out.WriteString("else")
 		out.WriteString(ie.Alternative.String())
@@ -169,6 +384,111 @@ func (ie *IfExpression) String() string {
 	return out.String()
 }
 
+// Node of `name = value`, it rebinds an existing variable
+type AssignExpression struct {
+	Token token.Token // The = token
+	Name  *Identifier
+	Value Expression
+}
+
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) String() string {
+	return ae.Name.String() + " = " + ae.Value.String()
+}
+
+// Node of `left[index] = value`, it replaces a slot of an array or hash in
+// place. Target.Left may itself be an index expression, as in `a[0][1] = v`
+type IndexAssignExpression struct {
+	Token  token.Token // The = token
+	Target *IndexExpression
+	Value  Expression
+}
+
+func (ia *IndexAssignExpression) expressionNode()      {}
+func (ia *IndexAssignExpression) TokenLiteral() string { return ia.Token.Literal }
+func (ia *IndexAssignExpression) String() string {
+	return ia.Target.String() + " = " + ia.Value.String()
+}
+
+// Node of `repeat { ... } until (condition)`, the body runs at least once
+// and then again as long as the condition is falsy
+type RepeatExpression struct {
+	Token     token.Token // The repeat identifier
+	Body      *BlockStatement
+	Condition Expression
+}
+
+func (re *RepeatExpression) expressionNode()      {}
+func (re *RepeatExpression) TokenLiteral() string { return re.Token.Literal }
+func (re *RepeatExpression) String() string {
+	return "repeat " + re.Body.String() + " until " + re.Condition.String()
+}
+
+// Node of `do { ... } while (condition)`, the body runs at least once and
+// then again as long as the condition is truthy
+type DoWhileExpression struct {
+	Token     token.Token // The DO token
+	Body      *BlockStatement
+	Condition Expression
+}
+
+func (dw *DoWhileExpression) expressionNode()      {}
+func (dw *DoWhileExpression) TokenLiteral() string { return dw.Token.Literal }
+func (dw *DoWhileExpression) String() string {
+	return "do " + dw.Body.String() + " while " + dw.Condition.String()
+}
+
+// Node of `for (x in iterable) { ... }`, it runs the body for every element
+// of an array or key of a hash. In `for (k, v in iterable)` Key is bound to
+// the index or key and Variable to the element or value
+type ForInExpression struct {
+	Token    token.Token // The FOR token
+	Key      *Identifier
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fi *ForInExpression) expressionNode()      {}
+func (fi *ForInExpression) TokenLiteral() string { return fi.Token.Literal }
+func (fi *ForInExpression) String() string {
+	variables := fi.Variable.String()
+	if fi.Key != nil {
+		variables = fi.Key.String() + ", " + variables
+	}
+	return "for (" + variables + " in " + fi.Iterable.String() + ") " + fi.Body.String()
+}
+
+// Node of `try { ... } catch (e) { ... }`, Param is nil when the catch
+// clause does not bind the error
+type TryExpression struct {
+	Token      token.Token // The TRY token
+	Block      *BlockStatement
+	Param      *Identifier // bound to the error message, may be nil
+	ErrorParam *Identifier // bound to the caught error itself, may be nil
+	Handler    *BlockStatement
+}
+
+func (te *TryExpression) expressionNode()      {}
+func (te *TryExpression) TokenLiteral() string { return te.Token.Literal }
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(te.Block.String())
+	out.WriteString(" catch")
+	if te.ErrorParam != nil {
+		out.WriteString("(" + te.Param.String() + ", " + te.ErrorParam.String() + ")")
+	} else if te.Param != nil {
+		out.WriteString("(" + te.Param.String() + ")")
+	}
+	out.WriteString(" ")
+	out.WriteString(te.Handler.String())
+
+	return out.String()
+}
+
 type BlockStatement struct {
 	Token      token.Token
 	Statements []Statement
@@ -211,6 +531,26 @@ func (fl *FunctionLiteral) String() string {
 	return out.String()
 }
 
+// Node of `macro(x, y) { ... }`. Macros are bound by top-level let
+// statements and expanded before the program is evaluated, receiving their
+// arguments unevaluated as quotes
+type MacroLiteral struct {
+	Token      token.Token // The MACRO token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (ml *MacroLiteral) expressionNode()      {}
+func (ml *MacroLiteral) TokenLiteral() string { return ml.Token.Literal }
+func (ml *MacroLiteral) String() string {
+	params := []string{}
+	for _, p := range ml.Parameters {
+		params = append(params, p.String())
+	}
+
+	return ml.TokenLiteral() + "(" + strings.Join(params, ", ") + ") " + ml.Body.String()
+}
+
 type CallExpression struct {
 	Token     token.Token
 	Function  Expression
@@ -235,6 +575,19 @@ func (ce *CallExpression) String() string {
 	return out.String()
 }
 
+// Node of a keyword argument in a call, e.g. `age = 30` in `f(age = 30)`
+type KeywordArgument struct {
+	Token token.Token // The IDENT token of the name
+	Name  *Identifier
+	Value Expression
+}
+
+func (ka *KeywordArgument) expressionNode()      {}
+func (ka *KeywordArgument) TokenLiteral() string { return ka.Token.Literal }
+func (ka *KeywordArgument) String() string {
+	return ka.Name.String() + " = " + ka.Value.String()
+}
+
 type StringLiteral struct {
 	Token token.Token
 	Value string
@@ -266,6 +619,29 @@ func (al *ArrayLiteral) String() string {
 	return out.String()
 }
 
+// Node of `...expr` inside an array literal or the arguments of a call
+type SpreadExpression struct {
+	Token token.Token // The ELLIPSIS token
+	Value Expression
+}
+
+func (se *SpreadExpression) expressionNode()      {}
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadExpression) String() string       { return "..." + se.Value.String() }
+
+// Node of `object.property`, reading a field or method of a struct instance
+type MemberExpression struct {
+	Token    token.Token // The . token
+	Object   Expression
+	Property *Identifier
+}
+
+func (me *MemberExpression) expressionNode()      {}
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MemberExpression) String() string {
+	return "(" + me.Object.String() + "." + me.Property.String() + ")"
+}
+
 type IndexExpression struct {
 	Token token.Token
 	Left  Expression
@@ -288,6 +664,7 @@ func (ie *IndexExpression) String() string {
 type HashLiteral struct {
 	Token token.Token
 	Pairs map[Expression]Expression
+	Keys  []Expression // keys of Pairs in source order
 }
 
 func (hl *HashLiteral) expressionNode()      {}
@@ -297,8 +674,8 @@ func (hl *HashLiteral) String() string {
 
 	pairs := []string{}
 
-	for key, val := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+val.String())
+	for _, key := range hl.Keys {
+		pairs = append(pairs, key.String()+":"+hl.Pairs[key].String())
 	}
 
 	out.WriteString("{")
@@ -25,3 +25,166 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() return wrong value, got :%s", program.String())
 	}
 }
+
+func TestWalk(t *testing.T) {
+	// let x = add(1, y);
+	program := &Program{
+		Statements: []Statement{
+			&LetStatement{
+				Token: token.Token{Type: token.LET, Literal: "let"},
+				Name:  &Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}, Value: "x"},
+				Value: &CallExpression{
+					Token:    token.Token{Type: token.LPAREN, Literal: "("},
+					Function: &Identifier{Token: token.Token{Type: token.IDENT, Literal: "add"}, Value: "add"},
+					Arguments: []Expression{
+						&IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "1"}, Value: 1},
+						&Identifier{Token: token.Token{Type: token.IDENT, Literal: "y"}, Value: "y"},
+					},
+				},
+			},
+		},
+	}
+
+	visited := []string{}
+	Walk(program, func(node Node) bool {
+		if _, ok := node.(*Program); !ok {
+			visited = append(visited, node.TokenLiteral())
+		}
+		return true
+	})
+
+	expected := []string{"let", "x", "(", "add", "1", "y"}
+	if len(visited) != len(expected) {
+		t.Fatalf("visited wrong nodes, expected: %v, got: %v", expected, visited)
+	}
+	for i := range expected {
+		if visited[i] != expected[i] {
+			t.Fatalf("visited wrong nodes, expected: %v, got: %v", expected, visited)
+		}
+	}
+
+	count := 0
+	Walk(program, func(node Node) bool {
+		count++
+		_, isProgram := node.(*Program)
+		return isProgram
+	})
+	if count != 2 {
+		t.Errorf("Walk did not stop descending, visited %d nodes", count)
+	}
+}
+
+func TestModify(t *testing.T) {
+	one := func() Expression { return &IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "1"}, Value: 1} }
+	two := func() Expression { return &IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "2"}, Value: 2} }
+	block := func() *BlockStatement {
+		return &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}}
+	}
+
+	turnOneIntoTwo := func(node Node) Node {
+		integer, ok := node.(*IntegerLiteral)
+		if !ok || integer.Value != 1 {
+			return node
+		}
+		return two()
+	}
+
+	hash := &HashLiteral{Pairs: map[Expression]Expression{}}
+	for _, key := range []Expression{one(), &StringLiteral{Token: token.Token{Type: token.STRING, Literal: "a"}, Value: "a"}} {
+		hash.Keys = append(hash.Keys, key)
+		hash.Pairs[key] = one()
+	}
+
+	tests := []struct {
+		input    Node
+		expected string
+	}{
+		{one(), "2"},
+		{&Program{Statements: []Statement{&ExpressionStatement{Expression: one()}}}, "2"},
+		{&InfixExpression{Left: one(), Operator: "+", Right: two()}, "(2 + 2)"},
+		{&PrefixExpression{Operator: "-", Right: one()}, "(-2)"},
+		{&IndexExpression{Left: one(), Index: one()}, "(2[2])"},
+		{&IfExpression{Condition: one(), Consequence: block(), Alternative: block()}, "if2 2else2"},
+		{&ReturnStatement{Token: token.Token{Literal: "return"}, ReturnValue: one()}, "return 2;"},
+		{&LetStatement{Token: token.Token{Literal: "let"}, Name: &Identifier{Value: "x"}, Value: one()}, "let x = 2;"},
+		{&FunctionLiteral{Token: token.Token{Literal: "fn"}, Parameters: []*Identifier{}, Body: block()}, "fn() 2"},
+		{&CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{one(), two()}}, "f(2, 2)"},
+		{&ArrayLiteral{Elements: []Expression{one(), one()}}, "[2, 2]"},
+		{hash, "{2:2, a:2}"},
+	}
+
+	for _, tt := range tests {
+		modified := Modify(tt.input, turnOneIntoTwo)
+		if modified.String() != tt.expected {
+			t.Errorf("not modified, expected: %q, got: %q", tt.expected, modified.String())
+		}
+	}
+
+	// a replacement that does not fit its place is ignored
+	let := &LetStatement{Token: token.Token{Literal: "let"}, Name: &Identifier{Value: "x"}, Value: one()}
+	modified := Modify(let, func(node Node) Node {
+		if _, ok := node.(*IntegerLiteral); ok {
+			return &ReturnStatement{ReturnValue: two()}
+		}
+		return node
+	})
+	if modified.String() != "let x = 1;" {
+		t.Errorf("misfitting replacement not ignored, got: %q", modified.String())
+	}
+}
+
+func TestEqual(t *testing.T) {
+	at := func(line, column int) token.Token {
+		return token.Token{Type: token.IDENT, Literal: "x", Line: line, Column: column}
+	}
+	var missing *BlockStatement
+
+	tests := []struct {
+		a, b  Node
+		equal bool
+	}{
+		{&Identifier{Token: at(1, 1), Value: "x"}, &Identifier{Token: at(7, 3), Value: "x"}, true},
+		{&Identifier{Value: "x"}, &Identifier{Value: "y"}, false},
+		{&Identifier{Value: "x"}, &StringLiteral{Value: "x"}, false},
+		{nil, nil, true},
+		{missing, nil, true},
+		{missing, &BlockStatement{}, false},
+		{&IfExpression{Condition: &Boolean{Value: true}, Consequence: &BlockStatement{}},
+			&IfExpression{Condition: &Boolean{Value: true}, Consequence: &BlockStatement{}, Alternative: &BlockStatement{}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.equal {
+			t.Errorf("Equal(%#v, %#v) wrong, expected: %t, got: %t", tt.a, tt.b, tt.equal, got)
+		}
+	}
+}
+
+func TestCopy(t *testing.T) {
+	original := &InfixExpression{
+		Operator: "+",
+		Left:     &IntegerLiteral{Token: token.Token{Literal: "1"}, Value: 1},
+		Right:    &CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{&Identifier{Value: "x"}}},
+	}
+
+	copied := Copy(original)
+	if !Equal(original, copied) {
+		t.Fatalf("copy not equal to original, got: %q", copied.String())
+	}
+
+	Modify(copied, func(node Node) Node {
+		if ident, ok := node.(*Identifier); ok && ident.Value == "x" {
+			return &Identifier{Value: "y"}
+		}
+		if literal, ok := node.(*IntegerLiteral); ok {
+			literal.Value = 2
+		}
+		return node
+	})
+	if original.String() != "(1 + f(x))" || original.Left.(*IntegerLiteral).Value != 1 {
+		t.Errorf("original changed through its copy, got: %q", original.String())
+	}
+	if Copy(nil) != nil {
+		t.Errorf("copy of nil not nil")
+	}
+}
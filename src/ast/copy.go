@@ -0,0 +1,187 @@
+package ast
+
+// Copy returns a deep copy of node, sharing nothing with it, so the copy can
+// be changed with Modify without touching the original. Nil stays nil.
+func Copy(node Node) Node {
+	if isNil(node) {
+		return nil
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		return &Program{Statements: copyStatements(node.Statements)}
+	case *BlockStatement:
+		return copyBlock(node)
+	case *LetStatement:
+		return &LetStatement{Token: node.Token, Name: copyIdentifier(node.Name), Value: copyExpression(node.Value)}
+	case *DestructuringStatement:
+		return &DestructuringStatement{Token: node.Token, Names: copyIdentifiers(node.Names), Value: copyExpression(node.Value)}
+	case *ParallelLetStatement:
+		return &ParallelLetStatement{Token: node.Token, Names: copyIdentifiers(node.Names), Values: copyExpressions(node.Values)}
+	case *ParallelAssignStatement:
+		return &ParallelAssignStatement{Token: node.Token, Names: copyIdentifiers(node.Names), Values: copyExpressions(node.Values)}
+	case *StructStatement:
+		methods := make([]*StructMethod, len(node.Methods))
+		for i, method := range node.Methods {
+			methods[i] = &StructMethod{Name: copyIdentifier(method.Name), Function: copyFunction(method.Function)}
+		}
+		return &StructStatement{Token: node.Token, Name: copyIdentifier(node.Name), Fields: copyIdentifiers(node.Fields), Methods: methods}
+	case *GlobalStatement:
+		return &GlobalStatement{Token: node.Token, Name: copyIdentifier(node.Name), Value: copyExpression(node.Value)}
+	case *ReturnStatement:
+		return &ReturnStatement{Token: node.Token, ReturnValue: copyExpression(node.ReturnValue)}
+	case *BreakStatement:
+		return &BreakStatement{Token: node.Token}
+	case *ContinueStatement:
+		return &ContinueStatement{Token: node.Token}
+	case *DeferStatement:
+		return &DeferStatement{Token: node.Token, Expression: copyExpression(node.Expression)}
+	case *ExpressionStatement:
+		return &ExpressionStatement{Token: node.Token, Expression: copyExpression(node.Expression)}
+	case *Identifier:
+		return copyIdentifier(node)
+	case *IntegerLiteral:
+		return &IntegerLiteral{Token: node.Token, Value: node.Value}
+	case *FloatLiteral:
+		return &FloatLiteral{Token: node.Token, Value: node.Value}
+	case *StringLiteral:
+		return &StringLiteral{Token: node.Token, Value: node.Value}
+	case *Boolean:
+		return &Boolean{Token: node.Token, Value: node.Value}
+	case *NullLiteral:
+		return &NullLiteral{Token: node.Token}
+	case *PrefixExpression:
+		return &PrefixExpression{Token: node.Token, Operator: node.Operator, Right: copyExpression(node.Right)}
+	case *PostfixExpression:
+		return &PostfixExpression{Token: node.Token, Name: copyIdentifier(node.Name), Operator: node.Operator}
+	case *InfixExpression:
+		return &InfixExpression{Token: node.Token, Left: copyExpression(node.Left), Right: copyExpression(node.Right), Operator: node.Operator}
+	case *AssignExpression:
+		return &AssignExpression{Token: node.Token, Name: copyIdentifier(node.Name), Value: copyExpression(node.Value)}
+	case *IndexAssignExpression:
+		target, _ := Copy(node.Target).(*IndexExpression)
+		return &IndexAssignExpression{Token: node.Token, Target: target, Value: copyExpression(node.Value)}
+	case *IfExpression:
+		var elseIfs []*ElseIfBranch
+		if node.ElseIfs != nil {
+			elseIfs = make([]*ElseIfBranch, len(node.ElseIfs))
+		}
+		for i, branch := range node.ElseIfs {
+			elseIfs[i] = &ElseIfBranch{Token: branch.Token, Condition: copyExpression(branch.Condition), Consequence: copyBlock(branch.Consequence)}
+		}
+		return &IfExpression{
+			Token:       node.Token,
+			Condition:   copyExpression(node.Condition),
+			Consequence: copyBlock(node.Consequence),
+			ElseIfs:     elseIfs,
+			Alternative: copyBlock(node.Alternative),
+		}
+	case *RepeatExpression:
+		return &RepeatExpression{Token: node.Token, Body: copyBlock(node.Body), Condition: copyExpression(node.Condition)}
+	case *DoWhileExpression:
+		return &DoWhileExpression{Token: node.Token, Body: copyBlock(node.Body), Condition: copyExpression(node.Condition)}
+	case *ForInExpression:
+		return &ForInExpression{
+			Token:    node.Token,
+			Key:      copyIdentifier(node.Key),
+			Variable: copyIdentifier(node.Variable),
+			Iterable: copyExpression(node.Iterable),
+			Body:     copyBlock(node.Body),
+		}
+	case *TryExpression:
+		return &TryExpression{
+			Token:      node.Token,
+			Block:      copyBlock(node.Block),
+			Param:      copyIdentifier(node.Param),
+			ErrorParam: copyIdentifier(node.ErrorParam),
+			Handler:    copyBlock(node.Handler),
+		}
+	case *FunctionLiteral:
+		return copyFunction(node)
+	case *MacroLiteral:
+		return &MacroLiteral{Token: node.Token, Parameters: copyIdentifiers(node.Parameters), Body: copyBlock(node.Body)}
+	case *CallExpression:
+		return &CallExpression{Token: node.Token, Function: copyExpression(node.Function), Arguments: copyExpressions(node.Arguments)}
+	case *KeywordArgument:
+		return &KeywordArgument{Token: node.Token, Name: copyIdentifier(node.Name), Value: copyExpression(node.Value)}
+	case *ArrayLiteral:
+		return &ArrayLiteral{Token: node.Token, Elements: copyExpressions(node.Elements)}
+	case *SpreadExpression:
+		return &SpreadExpression{Token: node.Token, Value: copyExpression(node.Value)}
+	case *IndexExpression:
+		return &IndexExpression{Token: node.Token, Left: copyExpression(node.Left), Index: copyExpression(node.Index)}
+	case *MemberExpression:
+		return &MemberExpression{Token: node.Token, Object: copyExpression(node.Object), Property: copyIdentifier(node.Property)}
+	case *HashLiteral:
+		hash := &HashLiteral{Token: node.Token, Pairs: make(map[Expression]Expression, len(node.Pairs))}
+		hash.Keys = make([]Expression, len(node.Keys))
+		for i, key := range node.Keys {
+			hash.Keys[i] = copyExpression(key)
+			hash.Pairs[hash.Keys[i]] = copyExpression(node.Pairs[key])
+		}
+		return hash
+	default:
+		return node
+	}
+}
+
+// The helpers below keep the static types of the copied children, which
+// Copy itself returns as plain Nodes.
+func copyExpression(exp Expression) Expression {
+	copied, _ := Copy(exp).(Expression)
+	return copied
+}
+
+func copyIdentifier(ident *Identifier) *Identifier {
+	if ident == nil {
+		return nil
+	}
+	return &Identifier{Token: ident.Token, Value: ident.Value}
+}
+
+func copyBlock(block *BlockStatement) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	return &BlockStatement{Token: block.Token, Statements: copyStatements(block.Statements)}
+}
+
+func copyFunction(function *FunctionLiteral) *FunctionLiteral {
+	if function == nil {
+		return nil
+	}
+	return &FunctionLiteral{Token: function.Token, Parameters: copyIdentifiers(function.Parameters), Body: copyBlock(function.Body)}
+}
+
+func copyExpressions(exps []Expression) []Expression {
+	if exps == nil {
+		return nil
+	}
+	copied := make([]Expression, len(exps))
+	for i, exp := range exps {
+		copied[i] = copyExpression(exp)
+	}
+	return copied
+}
+
+func copyIdentifiers(idents []*Identifier) []*Identifier {
+	if idents == nil {
+		return nil
+	}
+	copied := make([]*Identifier, len(idents))
+	for i, ident := range idents {
+		copied[i] = copyIdentifier(ident)
+	}
+	return copied
+}
+
+func copyStatements(statements []Statement) []Statement {
+	if statements == nil {
+		return nil
+	}
+	copied := make([]Statement, len(statements))
+	for i, stm := range statements {
+		copied[i], _ = Copy(stm).(Statement)
+	}
+	return copied
+}
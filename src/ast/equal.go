@@ -0,0 +1,205 @@
+package ast
+
+import "reflect"
+
+// Equal reports whether a and b are the same tree: nodes of the same kinds
+// with equal names, operators and literal values. Tokens are not compared,
+// so the same code parsed at different positions or with different spacing
+// is equal. Nil nodes, typed or not, are only equal to each other.
+func Equal(a, b Node) bool {
+	if isNil(a) || isNil(b) {
+		return isNil(a) && isNil(b)
+	}
+
+	switch a := a.(type) {
+	case *Program:
+		b, ok := b.(*Program)
+		return ok && equalStatements(a.Statements, b.Statements)
+	case *BlockStatement:
+		b, ok := b.(*BlockStatement)
+		return ok && equalStatements(a.Statements, b.Statements)
+	case *LetStatement:
+		b, ok := b.(*LetStatement)
+		return ok && Equal(a.Name, b.Name) && Equal(a.Value, b.Value)
+	case *DestructuringStatement:
+		b, ok := b.(*DestructuringStatement)
+		return ok && equalIdentifiers(a.Names, b.Names) && Equal(a.Value, b.Value)
+	case *ParallelLetStatement:
+		b, ok := b.(*ParallelLetStatement)
+		return ok && equalIdentifiers(a.Names, b.Names) && equalExpressions(a.Values, b.Values)
+	case *ParallelAssignStatement:
+		b, ok := b.(*ParallelAssignStatement)
+		return ok && equalIdentifiers(a.Names, b.Names) && equalExpressions(a.Values, b.Values)
+	case *StructStatement:
+		b, ok := b.(*StructStatement)
+		if !ok || !Equal(a.Name, b.Name) || !equalIdentifiers(a.Fields, b.Fields) || len(a.Methods) != len(b.Methods) {
+			return false
+		}
+		for i, method := range a.Methods {
+			if !Equal(method.Name, b.Methods[i].Name) || !Equal(method.Function, b.Methods[i].Function) {
+				return false
+			}
+		}
+		return true
+	case *GlobalStatement:
+		b, ok := b.(*GlobalStatement)
+		return ok && Equal(a.Name, b.Name) && Equal(a.Value, b.Value)
+	case *ReturnStatement:
+		b, ok := b.(*ReturnStatement)
+		return ok && Equal(a.ReturnValue, b.ReturnValue)
+	case *BreakStatement:
+		_, ok := b.(*BreakStatement)
+		return ok
+	case *ContinueStatement:
+		_, ok := b.(*ContinueStatement)
+		return ok
+	case *DeferStatement:
+		b, ok := b.(*DeferStatement)
+		return ok && Equal(a.Expression, b.Expression)
+	case *ExpressionStatement:
+		b, ok := b.(*ExpressionStatement)
+		return ok && Equal(a.Expression, b.Expression)
+	case *Identifier:
+		b, ok := b.(*Identifier)
+		return ok && a.Value == b.Value
+	case *IntegerLiteral:
+		b, ok := b.(*IntegerLiteral)
+		return ok && a.Value == b.Value
+	case *FloatLiteral:
+		b, ok := b.(*FloatLiteral)
+		return ok && a.Value == b.Value
+	case *StringLiteral:
+		b, ok := b.(*StringLiteral)
+		return ok && a.Value == b.Value
+	case *Boolean:
+		b, ok := b.(*Boolean)
+		return ok && a.Value == b.Value
+	case *NullLiteral:
+		_, ok := b.(*NullLiteral)
+		return ok
+	case *PrefixExpression:
+		b, ok := b.(*PrefixExpression)
+		return ok && a.Operator == b.Operator && Equal(a.Right, b.Right)
+	case *PostfixExpression:
+		b, ok := b.(*PostfixExpression)
+		return ok && a.Operator == b.Operator && Equal(a.Name, b.Name)
+	case *InfixExpression:
+		b, ok := b.(*InfixExpression)
+		return ok && a.Operator == b.Operator && Equal(a.Left, b.Left) && Equal(a.Right, b.Right)
+	case *AssignExpression:
+		b, ok := b.(*AssignExpression)
+		return ok && Equal(a.Name, b.Name) && Equal(a.Value, b.Value)
+	case *IndexAssignExpression:
+		b, ok := b.(*IndexAssignExpression)
+		return ok && Equal(a.Target, b.Target) && Equal(a.Value, b.Value)
+	case *IfExpression:
+		b, ok := b.(*IfExpression)
+		if !ok || !Equal(a.Condition, b.Condition) || !Equal(a.Consequence, b.Consequence) ||
+			!Equal(a.Alternative, b.Alternative) || len(a.ElseIfs) != len(b.ElseIfs) {
+			return false
+		}
+		for i, branch := range a.ElseIfs {
+			if !Equal(branch.Condition, b.ElseIfs[i].Condition) || !Equal(branch.Consequence, b.ElseIfs[i].Consequence) {
+				return false
+			}
+		}
+		return true
+	case *RepeatExpression:
+		b, ok := b.(*RepeatExpression)
+		return ok && Equal(a.Body, b.Body) && Equal(a.Condition, b.Condition)
+	case *DoWhileExpression:
+		b, ok := b.(*DoWhileExpression)
+		return ok && Equal(a.Body, b.Body) && Equal(a.Condition, b.Condition)
+	case *ForInExpression:
+		b, ok := b.(*ForInExpression)
+		return ok && Equal(a.Key, b.Key) && Equal(a.Variable, b.Variable) &&
+			Equal(a.Iterable, b.Iterable) && Equal(a.Body, b.Body)
+	case *TryExpression:
+		b, ok := b.(*TryExpression)
+		return ok && Equal(a.Block, b.Block) && Equal(a.Param, b.Param) &&
+			Equal(a.ErrorParam, b.ErrorParam) && Equal(a.Handler, b.Handler)
+	case *FunctionLiteral:
+		b, ok := b.(*FunctionLiteral)
+		return ok && equalIdentifiers(a.Parameters, b.Parameters) && Equal(a.Body, b.Body)
+	case *MacroLiteral:
+		b, ok := b.(*MacroLiteral)
+		return ok && equalIdentifiers(a.Parameters, b.Parameters) && Equal(a.Body, b.Body)
+	case *CallExpression:
+		b, ok := b.(*CallExpression)
+		return ok && Equal(a.Function, b.Function) && equalExpressions(a.Arguments, b.Arguments)
+	case *KeywordArgument:
+		b, ok := b.(*KeywordArgument)
+		return ok && Equal(a.Name, b.Name) && Equal(a.Value, b.Value)
+	case *ArrayLiteral:
+		b, ok := b.(*ArrayLiteral)
+		return ok && equalExpressions(a.Elements, b.Elements)
+	case *SpreadExpression:
+		b, ok := b.(*SpreadExpression)
+		return ok && Equal(a.Value, b.Value)
+	case *IndexExpression:
+		b, ok := b.(*IndexExpression)
+		return ok && Equal(a.Left, b.Left) && Equal(a.Index, b.Index)
+	case *MemberExpression:
+		b, ok := b.(*MemberExpression)
+		return ok && Equal(a.Object, b.Object) && Equal(a.Property, b.Property)
+	case *HashLiteral:
+		b, ok := b.(*HashLiteral)
+		if !ok || !equalExpressions(a.Keys, b.Keys) {
+			return false
+		}
+		for i, key := range a.Keys {
+			if !Equal(a.Pairs[key], b.Pairs[b.Keys[i]]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// isNil also catches a nil pointer stored in a Node, which compares unequal
+// to nil itself.
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+func equalStatements(a, b []Statement) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalExpressions(a, b []Expression) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalIdentifiers(a, b []*Identifier) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package ast
+
+// ModifierFunc returns the node to put in place of the one it is given.
+type ModifierFunc func(Node) Node
+
+// Modify replaces the children of node with the result of modifying them,
+// in source order, and then returns modifier(node). Only expressions and
+// statements are passed on, not the names bound by let, parameters and the
+// like. A replacement of the wrong kind for its place, e.g. a statement
+// returned for an expression, is ignored and the child is kept.
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch node := node.(type) {
+	case *Program:
+		node.Statements = modifyStatements(node.Statements, modifier)
+	case *BlockStatement:
+		node.Statements = modifyStatements(node.Statements, modifier)
+	case *LetStatement:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *DestructuringStatement:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *ParallelLetStatement:
+		node.Values = modifyExpressions(node.Values, modifier)
+	case *ParallelAssignStatement:
+		node.Values = modifyExpressions(node.Values, modifier)
+	case *StructStatement:
+		for _, method := range node.Methods {
+			if function, ok := modifyExpression(method.Function, modifier).(*FunctionLiteral); ok {
+				method.Function = function
+			}
+		}
+	case *GlobalStatement:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *ReturnStatement:
+		node.ReturnValue = modifyExpression(node.ReturnValue, modifier)
+	case *DeferStatement:
+		node.Expression = modifyExpression(node.Expression, modifier)
+	case *ExpressionStatement:
+		node.Expression = modifyExpression(node.Expression, modifier)
+	case *PrefixExpression:
+		node.Right = modifyExpression(node.Right, modifier)
+	case *InfixExpression:
+		node.Left = modifyExpression(node.Left, modifier)
+		node.Right = modifyExpression(node.Right, modifier)
+	case *AssignExpression:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *IndexAssignExpression:
+		if target, ok := modifyExpression(node.Target, modifier).(*IndexExpression); ok {
+			node.Target = target
+		}
+		node.Value = modifyExpression(node.Value, modifier)
+	case *IfExpression:
+		node.Condition = modifyExpression(node.Condition, modifier)
+		node.Consequence = modifyBlock(node.Consequence, modifier)
+		for _, branch := range node.ElseIfs {
+			branch.Condition = modifyExpression(branch.Condition, modifier)
+			branch.Consequence = modifyBlock(branch.Consequence, modifier)
+		}
+		node.Alternative = modifyBlock(node.Alternative, modifier)
+	case *RepeatExpression:
+		node.Body = modifyBlock(node.Body, modifier)
+		node.Condition = modifyExpression(node.Condition, modifier)
+	case *DoWhileExpression:
+		node.Body = modifyBlock(node.Body, modifier)
+		node.Condition = modifyExpression(node.Condition, modifier)
+	case *ForInExpression:
+		node.Iterable = modifyExpression(node.Iterable, modifier)
+		node.Body = modifyBlock(node.Body, modifier)
+	case *TryExpression:
+		node.Block = modifyBlock(node.Block, modifier)
+		node.Handler = modifyBlock(node.Handler, modifier)
+	case *FunctionLiteral:
+		node.Body = modifyBlock(node.Body, modifier)
+	case *MacroLiteral:
+		node.Body = modifyBlock(node.Body, modifier)
+	case *CallExpression:
+		node.Function = modifyExpression(node.Function, modifier)
+		node.Arguments = modifyExpressions(node.Arguments, modifier)
+	case *KeywordArgument:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *ArrayLiteral:
+		node.Elements = modifyExpressions(node.Elements, modifier)
+	case *SpreadExpression:
+		node.Value = modifyExpression(node.Value, modifier)
+	case *IndexExpression:
+		node.Left = modifyExpression(node.Left, modifier)
+		node.Index = modifyExpression(node.Index, modifier)
+	case *MemberExpression:
+		node.Object = modifyExpression(node.Object, modifier)
+	case *HashLiteral:
+		pairs := make(map[Expression]Expression, len(node.Pairs))
+		for i, key := range node.Keys {
+			value := node.Pairs[key]
+			node.Keys[i] = modifyExpression(key, modifier)
+			pairs[node.Keys[i]] = modifyExpression(value, modifier)
+		}
+		node.Pairs = pairs
+	}
+
+	return modifier(node)
+}
+
+// modifyExpression and modifyBlock skip nil children, like walkExpression.
+func modifyExpression(exp Expression, modifier ModifierFunc) Expression {
+	if exp == nil {
+		return nil
+	}
+	if modified, ok := Modify(exp, modifier).(Expression); ok {
+		return modified
+	}
+	return exp
+}
+
+func modifyBlock(block *BlockStatement, modifier ModifierFunc) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	if modified, ok := Modify(block, modifier).(*BlockStatement); ok {
+		return modified
+	}
+	return block
+}
+
+func modifyExpressions(exps []Expression, modifier ModifierFunc) []Expression {
+	for i, exp := range exps {
+		exps[i] = modifyExpression(exp, modifier)
+	}
+	return exps
+}
+
+func modifyStatements(statements []Statement, modifier ModifierFunc) []Statement {
+	for i, stm := range statements {
+		if modified, ok := Modify(stm, modifier).(Statement); ok {
+			statements[i] = modified
+		}
+	}
+	return statements
+}
@@ -0,0 +1,152 @@
+package ast
+
+// Walk calls fn for node and then, if fn returns true, for each of its
+// children in source order.
+func Walk(node Node, fn func(Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		for _, s := range node.Statements {
+			Walk(s, fn)
+		}
+	case *BlockStatement:
+		for _, s := range node.Statements {
+			Walk(s, fn)
+		}
+	case *LetStatement:
+		Walk(node.Name, fn)
+		walkExpression(node.Value, fn)
+	case *DestructuringStatement:
+		for _, name := range node.Names {
+			Walk(name, fn)
+		}
+		walkExpression(node.Value, fn)
+	case *ParallelLetStatement:
+		for _, name := range node.Names {
+			Walk(name, fn)
+		}
+		for _, value := range node.Values {
+			walkExpression(value, fn)
+		}
+	case *ParallelAssignStatement:
+		for _, name := range node.Names {
+			Walk(name, fn)
+		}
+		for _, value := range node.Values {
+			walkExpression(value, fn)
+		}
+	case *StructStatement:
+		Walk(node.Name, fn)
+		for _, field := range node.Fields {
+			Walk(field, fn)
+		}
+		for _, method := range node.Methods {
+			Walk(method.Name, fn)
+			walkExpression(method.Function, fn)
+		}
+	case *GlobalStatement:
+		Walk(node.Name, fn)
+		walkExpression(node.Value, fn)
+	case *ReturnStatement:
+		walkExpression(node.ReturnValue, fn)
+	case *DeferStatement:
+		walkExpression(node.Expression, fn)
+	case *ExpressionStatement:
+		walkExpression(node.Expression, fn)
+	case *PrefixExpression:
+		walkExpression(node.Right, fn)
+	case *PostfixExpression:
+		Walk(node.Name, fn)
+	case *InfixExpression:
+		walkExpression(node.Left, fn)
+		walkExpression(node.Right, fn)
+	case *AssignExpression:
+		Walk(node.Name, fn)
+		walkExpression(node.Value, fn)
+	case *IndexAssignExpression:
+		Walk(node.Target, fn)
+		walkExpression(node.Value, fn)
+	case *IfExpression:
+		walkExpression(node.Condition, fn)
+		walkBlock(node.Consequence, fn)
+		for _, branch := range node.ElseIfs {
+			walkExpression(branch.Condition, fn)
+			walkBlock(branch.Consequence, fn)
+		}
+		walkBlock(node.Alternative, fn)
+	case *RepeatExpression:
+		walkBlock(node.Body, fn)
+		walkExpression(node.Condition, fn)
+	case *DoWhileExpression:
+		walkBlock(node.Body, fn)
+		walkExpression(node.Condition, fn)
+	case *ForInExpression:
+		if node.Key != nil {
+			Walk(node.Key, fn)
+		}
+		Walk(node.Variable, fn)
+		walkExpression(node.Iterable, fn)
+		walkBlock(node.Body, fn)
+	case *TryExpression:
+		walkBlock(node.Block, fn)
+		if node.Param != nil {
+			Walk(node.Param, fn)
+		}
+		if node.ErrorParam != nil {
+			Walk(node.ErrorParam, fn)
+		}
+		walkBlock(node.Handler, fn)
+	case *FunctionLiteral:
+		for _, p := range node.Parameters {
+			Walk(p, fn)
+		}
+		walkBlock(node.Body, fn)
+	case *MacroLiteral:
+		for _, p := range node.Parameters {
+			Walk(p, fn)
+		}
+		walkBlock(node.Body, fn)
+	case *CallExpression:
+		walkExpression(node.Function, fn)
+		for _, a := range node.Arguments {
+			walkExpression(a, fn)
+		}
+	case *KeywordArgument:
+		Walk(node.Name, fn)
+		walkExpression(node.Value, fn)
+	case *ArrayLiteral:
+		for _, el := range node.Elements {
+			walkExpression(el, fn)
+		}
+	case *SpreadExpression:
+		walkExpression(node.Value, fn)
+	case *IndexExpression:
+		walkExpression(node.Left, fn)
+		walkExpression(node.Index, fn)
+	case *MemberExpression:
+		walkExpression(node.Object, fn)
+		Walk(node.Property, fn)
+	case *HashLiteral:
+		for _, key := range node.Keys {
+			walkExpression(key, fn)
+			walkExpression(node.Pairs[key], fn)
+		}
+	}
+}
+
+// walkExpression and walkBlock skip nil children, which a typed nil pointer
+// stored in a Node interface would not be.
+func walkExpression(exp Expression, fn func(Node) bool) {
+	if exp != nil {
+		Walk(exp, fn)
+	}
+}
+
+func walkBlock(block *BlockStatement, fn func(Node) bool) {
+	if block != nil {
+		Walk(block, fn)
+	}
+}
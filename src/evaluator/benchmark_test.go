@@ -0,0 +1,48 @@
+package evaluator
+
+import (
+	"testing"
+
+	"monkey/src/lexer"
+	"monkey/src/object"
+	"monkey/src/parser"
+)
+
+func benchmarkEval(b *testing.B, input string) {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		b.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result := Eval(program, object.NewEnvironment())
+		if isError(result) {
+			b.Fatalf("evaluation failed: %s", result.Inspect())
+		}
+	}
+}
+
+func BenchmarkFibonacci(b *testing.B) {
+	benchmarkEval(b, `
+	let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } };
+	fib(15);
+	`)
+}
+
+func BenchmarkArithmeticLoop(b *testing.B) {
+	benchmarkEval(b, `
+	let loop = fn(i, acc) { if (i == 0) { acc } else { loop(i - 1, acc + i * 2 % 7) } };
+	loop(200, 0);
+	`)
+}
+
+func BenchmarkLoopIndexing(b *testing.B) {
+	benchmarkEval(b, `
+	let arr = range(100);
+	loop(len(arr), 0, fn(acc, i) { acc + arr[i] });
+	`)
+}
@@ -1,13 +1,22 @@
 package evaluator
 
 import (
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"time"
 
 	"monkey/src/object"
 )
 
 var builtins = map[string]*object.Builtin{
 	"len": {
+		Signature:   "len(x) -> INTEGER",
+		Description: "length of a string, array, hash, set or bytes",
+		Arity:       1,
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. Got: %d, take: 1", len(args))
@@ -15,9 +24,15 @@ var builtins = map[string]*object.Builtin{
 
 			switch arg := args[0].(type) {
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
+				return newInteger(int64(len(arg.Value)))
 			case *object.Array:
-				return &object.Integer{Value: int64(len(arg.Elements))}
+				return newInteger(int64(len(arg.Elements)))
+			case *object.Hash:
+				return newInteger(int64(len(arg.Pairs)))
+			case *object.Set:
+				return newInteger(int64(len(arg.Elements)))
+			case *object.Bytes:
+				return newInteger(int64(len(arg.Value)))
 			default:
 				return newError("argument to `len` not supported: %s", arg.Type())
 			}
@@ -25,6 +40,9 @@ var builtins = map[string]*object.Builtin{
 	},
 
 	"first": {
+		Signature:   "first(arr) -> ANY",
+		Description: "first element of an array, or null if it is empty",
+		Arity:       1,
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. Got: %d, take: 1", len(args))
@@ -43,6 +61,9 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"last": {
+		Signature:   "last(arr) -> ANY",
+		Description: "last element of an array, or null if it is empty",
+		Arity:       1,
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. Got: %d, take: 1", len(args))
@@ -62,6 +83,9 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"rest": {
+		Signature:   "rest(arr) -> ARRAY",
+		Description: "copy of an array without its first element",
+		Arity:       1,
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. got=%d, want=1",
@@ -82,6 +106,9 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"push": {
+		Signature:   "push(arr, x) -> ARRAY",
+		Description: "copy of an array with x appended",
+		Arity:       2,
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return newError("wrong number of arguments. got=%d, want=2",
@@ -99,12 +126,1355 @@ var builtins = map[string]*object.Builtin{
 			return &object.Array{Elements: newElements}
 		},
 	},
+	"zip": {
+		Signature:   "zip(a, b, ...) -> ARRAY",
+		Description: "arrays of the elements at the same index, up to the shortest array",
+		Arity:       2,
+		Variadic:    true,
+		Fn:          unlimited(builtinZip),
+	},
+	"chunk": {
+		Signature:   "chunk(arr, size) -> ARRAY",
+		Description: "arrays of at most size consecutive elements, the last possibly shorter",
+		Arity:       2,
+		Fn:          unlimited(builtinChunk),
+	},
+	"window": {
+		Signature:   "window(arr, size) -> ARRAY",
+		Description: "overlapping arrays of size consecutive elements, empty if size exceeds the length",
+		Arity:       2,
+		Fn:          unlimited(builtinWindow),
+	},
+	"count": {
+		Signature:   "count(haystack, needle) -> INTEGER",
+		Description: "non-overlapping occurrences of a substring, or elements equal to needle",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError("wrong number of arguments. got=%d, want=2", len(args))
+			}
+
+			switch haystack := args[0].(type) {
+			case *object.String:
+				needle, ok := args[1].(*object.String)
+				if !ok {
+					return newError("second argument to `count` must be STRING, got %s", args[1].Type())
+				}
+				if needle.Value == "" {
+					return newError("second argument to `count` must not be empty")
+				}
+				return newInteger(int64(strings.Count(haystack.Value, needle.Value)))
+			case *object.Array:
+				n := 0
+				for _, el := range haystack.Elements {
+					if object.Equal(el, args[1]) {
+						n++
+					}
+				}
+				return newInteger(int64(n))
+			default:
+				return newError("argument to `count` must be STRING or ARRAY, got %s", args[0].Type())
+			}
+		},
+	},
+	"frequencies": {
+		Signature:   "frequencies(arr) -> HASH",
+		Description: "number of times each distinct element occurs in an array",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return newError("argument to `frequencies` must be ARRAY, got %s", args[0].Type())
+			}
+
+			counts := object.NewHash()
+			for _, el := range arr.Elements {
+				key, ok := el.(object.Hashable)
+				if !ok {
+					return newError("unusable as hash key: %s", el.Type())
+				}
+
+				hashed := key.HashKey()
+				n := int64(0)
+				if pair, ok := counts.Pairs[hashed]; ok {
+					n = pair.Value.(*object.Integer).Value
+				}
+				counts.Set(hashed, object.HashPair{Key: el, Value: newInteger(n + 1)})
+			}
+			return counts
+		},
+	},
+	"divmod": {
+		Signature:   "divmod(a, b) -> ARRAY",
+		Description: "[a / b, a % b] for integers, e.g. `let [q, r] = divmod(17, 5)`",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError("wrong number of arguments. got=%d, want=2", len(args))
+			}
+			a, ok := args[0].(*object.Integer)
+			if !ok {
+				return newError("first argument to `divmod` must be INTEGER, got %s", args[0].Type())
+			}
+			b, ok := args[1].(*object.Integer)
+			if !ok {
+				return newError("second argument to `divmod` must be INTEGER, got %s", args[1].Type())
+			}
+			if b.Value == 0 {
+				return newError("division by zero: divmod(%d, %d)", a.Value, b.Value)
+			}
+
+			return &object.Array{Elements: []object.Object{
+				newInteger(a.Value / b.Value),
+				newInteger(a.Value % b.Value),
+			}}
+		},
+	},
+	"enumerate": {
+		Signature:   "enumerate(arr, start = 0) -> ARRAY",
+		Description: "[index, element] pairs of an array, counting from start",
+		Arity:       1,
+		Variadic:    true,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newError("wrong number of arguments. got=%d, want=1 or 2", len(args))
+			}
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return newError("argument to `enumerate` must be ARRAY, got %s", args[0].Type())
+			}
+			start := int64(0)
+			if len(args) == 2 {
+				n, ok := args[1].(*object.Integer)
+				if !ok {
+					return newError("second argument to `enumerate` must be INTEGER, got %s", args[1].Type())
+				}
+				start = n.Value
+			}
+
+			pairs := make([]object.Object, len(arr.Elements))
+			for i, el := range arr.Elements {
+				index := newInteger(start + int64(i))
+				pairs[i] = &object.Array{Elements: []object.Object{index, el}}
+			}
+			return &object.Array{Elements: pairs}
+		},
+	},
+	"take": {
+		Signature:   "take(seq, n) -> ARRAY|STRING",
+		Description: "first n elements of an array or runes of a string",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			return sliceBuiltin("take", args, func(length, n int) (int, int) {
+				return 0, n
+			})
+		},
+	},
+	"drop": {
+		Signature:   "drop(seq, n) -> ARRAY|STRING",
+		Description: "array or string without its first n elements",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			return sliceBuiltin("drop", args, func(length, n int) (int, int) {
+				return n, length
+			})
+		},
+	},
+	"unique": {
+		Signature:   "unique(arr) -> ARRAY",
+		Description: "elements of an array without duplicates, in first-seen order",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return newError("argument to `unique` must be ARRAY, got %s", args[0].Type())
+			}
+
+			seen := make(map[object.HashKey]bool)
+			elements := []object.Object{}
+			unhashable := []object.Object{}
+			for _, el := range arr.Elements {
+				if hashable, ok := el.(object.Hashable); ok {
+					key := hashable.HashKey()
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+					elements = append(elements, el)
+					continue
+				}
+
+				duplicate := false
+				for _, other := range unhashable {
+					if object.Equal(el, other) {
+						duplicate = true
+						break
+					}
+				}
+				if !duplicate {
+					unhashable = append(unhashable, el)
+					elements = append(elements, el)
+				}
+			}
+
+			return &object.Array{Elements: elements}
+		},
+	},
+	"repeat": {
+		Signature:   "repeat(seq, n) -> STRING|ARRAY",
+		Description: "string or array repeated n times",
+		Arity:       2,
+		Fn:          unlimited(builtinRepeat),
+	},
+	"keys": {
+		Signature:   "keys(hash) -> ARRAY",
+		Description: "keys of a hash in insertion order",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return hashEnumeration("keys", args, func(pair object.HashPair) object.Object {
+				return pair.Key
+			})
+		},
+	},
+	"values": {
+		Signature:   "values(hash) -> ARRAY",
+		Description: "values of a hash in insertion order",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return hashEnumeration("values", args, func(pair object.HashPair) object.Object {
+				return pair.Value
+			})
+		},
+	},
+	"entries": {
+		Signature:   "entries(hash) -> ARRAY",
+		Description: "[key, value] pairs of a hash in insertion order",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return hashEnumeration("entries", args, func(pair object.HashPair) object.Object {
+				return &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+			})
+		},
+	},
+	"fromEntries": {
+		Signature:   "fromEntries(arr) -> HASH",
+		Description: "hash built from [key, value] pairs, the inverse of entries",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return newError("argument to `fromEntries` must be ARRAY, got %s", args[0].Type())
+			}
+
+			hash := object.NewHash()
+			for i, el := range arr.Elements {
+				entry, ok := el.(*object.Array)
+				if !ok || len(entry.Elements) != 2 {
+					return newError("element %d of `fromEntries` must be a [key, value] pair, got %s", i, el.Inspect())
+				}
+				key, ok := entry.Elements[0].(object.Hashable)
+				if !ok {
+					return newError("unusable as hash key: %s", entry.Elements[0].Type())
+				}
+				hash.Set(key.HashKey(), object.HashPair{Key: entry.Elements[0], Value: entry.Elements[1]})
+			}
+
+			return hash
+		},
+	},
+	"isNaN": {
+		Signature:   "isNaN(x) -> BOOLEAN",
+		Description: "whether a number is the float NaN",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return floatPredicate("isNaN", args, math.IsNaN)
+		},
+	},
+	"isInf": {
+		Signature:   "isInf(x) -> BOOLEAN",
+		Description: "whether a number is the float Infinity or -Infinity",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return floatPredicate("isInf", args, func(f float64) bool {
+				return math.IsInf(f, 0)
+			})
+		},
+	},
+	"equals": {
+		Signature:   "equals(a, b) -> BOOLEAN",
+		Description: "deep structural comparison, values of different types are unequal",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError("wrong number of arguments. got=%d, want=2", len(args))
+			}
+			return nativeBoolToBooleanObject(object.Equal(args[0], args[1]))
+		},
+	},
+	"hashOf": {
+		Signature:   "hashOf(x) -> INTEGER",
+		Description: "integer derived from the hash key of x, the same for equal keys in every run",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			hashable, ok := args[0].(object.Hashable)
+			if !ok {
+				return newError("unusable as hash key: %s", args[0].Type())
+			}
+			return &object.Integer{Value: hashKeyValue(hashable.HashKey())}
+		},
+	},
+	"tag": {
+		Signature:   "tag(hash, name) -> HASH",
+		Description: "copy of a hash tagged with a type name, an empty name removes the tag",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError("wrong number of arguments. got=%d, want=2", len(args))
+			}
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return newError("first argument to `tag` must be HASH, got %s", args[0].Type())
+			}
+			name, ok := args[1].(*object.String)
+			if !ok {
+				return newError("second argument to `tag` must be STRING, got %s", args[1].Type())
+			}
+
+			tagged := object.NewHash()
+			tagged.Tag = name.Value
+			for _, pair := range hash.OrderedPairs() {
+				tagged.Set(pair.Key.(object.Hashable).HashKey(), pair)
+			}
+			return tagged
+		},
+	},
+	"tagged": {
+		Signature:   "tagged(value, name) -> BOOLEAN",
+		Description: "whether a value is a hash tagged with name or an instance of the struct name",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError("wrong number of arguments. got=%d, want=2", len(args))
+			}
+			name, ok := args[1].(*object.String)
+			if !ok {
+				return newError("second argument to `tagged` must be STRING, got %s", args[1].Type())
+			}
+
+			switch value := args[0].(type) {
+			case *object.Hash:
+				return nativeBoolToBooleanObject(value.Tag != "" && value.Tag == name.Value)
+			case *object.Instance:
+				return nativeBoolToBooleanObject(value.Struct.Name == name.Value)
+			default:
+				return FALSE
+			}
+		},
+	},
+	"freeze": {
+		Signature:   "freeze(x) -> ARRAY|HASH",
+		Description: "x made unchangeable in place, the containers in it are not affected",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return mutabilityBuiltin("freeze", args, func(arr *object.Array) { arr.Frozen = true },
+				func(hash *object.Hash) { hash.Frozen = true })
+		},
+	},
+	"seal": {
+		Signature:   "seal(hash) -> HASH",
+		Description: "hash kept from getting new keys in place, existing ones can still be replaced",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return newError("argument to `seal` must be HASH, got %s", args[0].Type())
+			}
+			hash.Sealed = true
+			return hash
+		},
+	},
+	"isFrozen": {
+		Signature:   "isFrozen(x) -> BOOLEAN",
+		Description: "whether x is a frozen array or hash",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			switch arg := args[0].(type) {
+			case *object.Array:
+				return nativeBoolToBooleanObject(arg.Frozen)
+			case *object.Hash:
+				return nativeBoolToBooleanObject(arg.Frozen)
+			default:
+				return FALSE
+			}
+		},
+	},
+	"isSealed": {
+		Signature:   "isSealed(x) -> BOOLEAN",
+		Description: "whether x is a sealed or frozen hash, or a frozen array",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			switch arg := args[0].(type) {
+			case *object.Array:
+				return nativeBoolToBooleanObject(arg.Frozen)
+			case *object.Hash:
+				return nativeBoolToBooleanObject(arg.Sealed || arg.Frozen)
+			default:
+				return FALSE
+			}
+		},
+	},
+	"panic": {
+		Signature:   "panic(msg) -> ERROR",
+		Description: "raise an error that try/catch cannot catch",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+
+			msg := args[0].Inspect()
+			return &object.Error{Message: "panic: " + msg, Fatal: true}
+		},
+	},
 	"put": {
+		Signature:   "put(...) -> NULL",
+		Description: "print each argument on its own line",
+		Arity:       0,
+		Variadic:    true,
 		Fn: func(args ...object.Object) object.Object {
 			for _, args := range args {
-				fmt.Println(args.Inspect())
+				fmt.Fprintln(Output, args.Inspect())
 			}
 			return NULL
 		},
 	},
+	"table": {
+		Signature:   "table(rows) -> NULL",
+		Description: "print an array of hashes as a table with a column per key",
+		Arity:       1,
+		Fn:          builtinTable,
+	},
+	"exit": {
+		Signature:   "exit(code = 0) -> NULL",
+		Description: "stop the program and exit with code",
+		Arity:       0,
+		Variadic:    true,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newError("wrong number of arguments. got=%d, want=0 or 1", len(args))
+			}
+			if len(args) == 0 {
+				return &object.Exit{Code: 0}
+			}
+			code, ok := args[0].(*object.Integer)
+			if !ok {
+				return newError("argument to `exit` must be INTEGER, got %s", args[0].Type())
+			}
+			return &object.Exit{Code: code.Value}
+		},
+	},
+	"readLine": {
+		Signature:   "readLine() -> STRING|NULL",
+		Description: "next line of input without its line break, null at the end of input",
+		Arity:       0,
+		Fn:          builtinReadLine,
+	},
+}
+
+// Builtins that call back into user functions go through applyFunction, which
+// would make the initializer of builtins cyclic, so they are registered here.
+func init() {
+	builtins["range"] = &object.Builtin{
+		Fn:          unlimited(builtinRange),
+		Signature:   "range(start = 0, end, step = 1) -> ARRAY",
+		Description: "integers from start up to, but excluding, end; a negative step counts down",
+		Arity:       1,
+		Variadic:    true,
+	}
+	// these need the environment to check the allocation limit before
+	// building their result, range also for cancellation
+	for name, fn := range map[string]func(*object.Environment, []object.Object) object.Object{
+		"range":    builtinRange,
+		"repeat":   builtinRepeat,
+		"padLeft":  builtinPadLeft,
+		"padRight": builtinPadRight,
+		"times":    builtinTimes,
+		"chunk":    builtinChunk,
+		"window":   builtinWindow,
+		"zip":      builtinZip,
+	} {
+		environmentBuiltins[name] = fn
+	}
+
+	builtins["map"] = &object.Builtin{
+		Fn:          builtinMap,
+		Signature:   "map(arr, fn) -> ARRAY",
+		Description: "results of fn(el) for every element, fn(el, i) also gets the index",
+		Arity:       2,
+	}
+	builtins["filter"] = &object.Builtin{
+		Fn:          builtinFilter,
+		Signature:   "filter(arr, fn) -> ARRAY",
+		Description: "elements for which fn(el) is truthy, fn(el, i) also gets the index",
+		Arity:       2,
+	}
+	builtins["reduce"] = &object.Builtin{
+		Fn:          builtinReduce,
+		Signature:   "reduce(arr, initial, fn) -> ANY",
+		Description: "fold fn(acc, el) over the elements, fn(acc, el, i) also gets the index",
+		Arity:       3,
+	}
+	builtins["scan"] = &object.Builtin{
+		Fn:          builtinScan,
+		Signature:   "scan(arr, initial, fn) -> ARRAY",
+		Description: "like reduce, but every intermediate accumulator starting with initial",
+		Arity:       3,
+	}
+	builtins["arity"] = &object.Builtin{
+		Fn:          builtinArity,
+		Signature:   "arity(fn) -> INTEGER",
+		Description: "number of parameters of a function, for builtins the number of required ones",
+		Arity:       1,
+	}
+	builtins["any"] = &object.Builtin{
+		Fn:          builtinAny,
+		Signature:   "any(arr, fn) -> BOOLEAN",
+		Description: "whether fn returns a truthy value for any element",
+		Arity:       2,
+	}
+	builtins["all"] = &object.Builtin{
+		Fn:          builtinAll,
+		Signature:   "all(arr, fn) -> BOOLEAN",
+		Description: "whether fn returns a truthy value for every element",
+		Arity:       2,
+	}
+	builtins["takeWhile"] = &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			return whileBuiltin("takeWhile", args, func(elements []object.Object, n int) []object.Object {
+				return elements[:n]
+			})
+		},
+		Signature:   "takeWhile(arr, fn) -> ARRAY",
+		Description: "leading elements for which fn returns a truthy value",
+		Arity:       2,
+	}
+	builtins["dropWhile"] = &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			return whileBuiltin("dropWhile", args, func(elements []object.Object, n int) []object.Object {
+				return elements[n:]
+			})
+		},
+		Signature:   "dropWhile(arr, fn) -> ARRAY",
+		Description: "elements after the leading ones for which fn returns a truthy value",
+		Arity:       2,
+	}
+	builtins["groupBy"] = &object.Builtin{
+		Fn:          builtinGroupBy,
+		Signature:   "groupBy(arr, fn) -> HASH",
+		Description: "elements grouped by the key fn returns for them",
+		Arity:       2,
+	}
+	builtins["minBy"] = &object.Builtin{
+		Fn:          func(args ...object.Object) object.Object { return extremeBy("minBy", "<", args) },
+		Signature:   "minBy(arr, fn) -> ANY",
+		Description: "element for which fn returns the smallest value, null if arr is empty",
+		Arity:       2,
+	}
+	builtins["maxBy"] = &object.Builtin{
+		Fn:          func(args ...object.Object) object.Object { return extremeBy("maxBy", ">", args) },
+		Signature:   "maxBy(arr, fn) -> ANY",
+		Description: "element for which fn returns the largest value, null if arr is empty",
+		Arity:       2,
+	}
+	builtins["loop"] = &object.Builtin{
+		Fn:          builtinLoop,
+		Signature:   "loop(n, initial, fn) -> ANY",
+		Description: "fold fn(acc, i) over 0..n-1 starting from initial",
+		Arity:       3,
+	}
+	builtins["times"] = &object.Builtin{
+		Fn:          unlimited(builtinTimes),
+		Signature:   "times(n, fn) -> ARRAY",
+		Description: "results of calling fn(i) for i in 0..n-1",
+		Arity:       2,
+	}
+	builtins["bench"] = &object.Builtin{
+		Fn:          builtinBench,
+		Signature:   "bench(fn, n) -> FLOAT",
+		Description: "average milliseconds a call fn() takes over n calls, the results are discarded",
+		Arity:       2,
+	}
+	builtins["str"] = &object.Builtin{
+		Fn:          builtinStr,
+		Signature:   "str(x) -> STRING",
+		Description: "x rendered as a string, hashes with a __str__ function are rendered by calling it",
+		Arity:       1,
+	}
+	builtins["apply"] = &object.Builtin{
+		Fn:          builtinApply,
+		Signature:   "apply(fn, args) -> ANY",
+		Description: "call fn with the elements of args as arguments",
+		Arity:       2,
+	}
+	builtins["help"] = &object.Builtin{
+		Fn:          builtinHelp,
+		Signature:   "help(name) -> STRING",
+		Description: "signature and description of a builtin function",
+		Arity:       1,
+	}
+	builtins["builtins"] = &object.Builtin{
+		Fn:          builtinBuiltins,
+		Signature:   "builtins() -> ARRAY",
+		Description: "sorted names of all builtin functions",
+		Arity:       0,
+	}
+}
+
+// functionArity returns the number of parameters fn takes, it reports false
+// for variadic builtins, which take a varying number of arguments.
+func functionArity(fn object.Object) (int, bool) {
+	switch fn := fn.(type) {
+	case *object.Function:
+		return len(fn.Parameters), true
+	case *object.BoundMethod:
+		return len(fn.Method.Parameters), true
+	case *object.Struct:
+		return len(fn.Fields), true
+	case *object.Builtin:
+		return fn.Arity, !fn.Variadic
+	default:
+		return 0, false
+	}
+}
+
+func builtinArity(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	if builtin, ok := args[0].(*object.Builtin); ok {
+		return newInteger(int64(builtin.Arity))
+	}
+	if arity, ok := functionArity(args[0]); ok {
+		return newInteger(int64(arity))
+	}
+	return newError("argument to `arity` must be FUNCTION, got %s", args[0].Type())
+}
+
+// applyWithIndex calls fn with args, followed by index if fn takes one more
+// parameter than len(args).
+func applyWithIndex(fn object.Object, args []object.Object, index int) object.Object {
+	if arity, ok := functionArity(fn); ok && arity == len(args)+1 {
+		args = append(args, newInteger(int64(index)))
+	}
+	return applyFunction(fn, args)
+}
+
+func builtinMap(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `map` must be ARRAY, got %s", args[0].Type())
+	}
+
+	elements := make([]object.Object, len(arr.Elements))
+	for i, el := range arr.Elements {
+		result := applyWithIndex(args[1], []object.Object{el}, i)
+		if isError(result) {
+			return result
+		}
+		elements[i] = result
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinFilter(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `filter` must be ARRAY, got %s", args[0].Type())
+	}
+
+	elements := []object.Object{}
+	for i, el := range arr.Elements {
+		result := applyWithIndex(args[1], []object.Object{el}, i)
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			elements = append(elements, el)
+		}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinReduce(args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=3", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("first argument to `reduce` must be ARRAY, got %s", args[0].Type())
+	}
+
+	acc := args[1]
+	for i, el := range arr.Elements {
+		acc = applyWithIndex(args[2], []object.Object{acc, el}, i)
+		if isError(acc) {
+			return acc
+		}
+	}
+
+	return acc
+}
+
+func builtinScan(args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=3", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("first argument to `scan` must be ARRAY, got %s", args[0].Type())
+	}
+
+	acc := args[1]
+	results := make([]object.Object, 0, len(arr.Elements)+1)
+	results = append(results, acc)
+	for i, el := range arr.Elements {
+		acc = applyWithIndex(args[2], []object.Object{acc, el}, i)
+		if isError(acc) {
+			return acc
+		}
+		results = append(results, acc)
+	}
+
+	return &object.Array{Elements: results}
+}
+
+func builtinAny(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.ARRAY_OBJ {
+		return newError("argument to `any` must be ARRAY, got %s", args[0].Type())
+	}
+
+	for _, el := range args[0].(*object.Array).Elements {
+		result := applyFunction(args[1], []object.Object{el})
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			return TRUE
+		}
+	}
+
+	return FALSE
+}
+
+func builtinAll(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.ARRAY_OBJ {
+		return newError("argument to `all` must be ARRAY, got %s", args[0].Type())
+	}
+
+	for _, el := range args[0].(*object.Array).Elements {
+		result := applyFunction(args[1], []object.Object{el})
+		if isError(result) {
+			return result
+		}
+		if !isTruthy(result) {
+			return FALSE
+		}
+	}
+
+	return TRUE
+}
+
+// sliceBuiltin backs take and drop: bounds maps the sequence length and the
+// clamped count to the [low, high) range that is copied out.
+func sliceBuiltin(name string, args []object.Object, bounds func(length, n int) (int, int)) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+
+	count, ok := args[1].(*object.Integer)
+	if !ok {
+		return newError("second argument to `%s` must be INTEGER, got %s", name, args[1].Type())
+	}
+	if count.Value < 0 {
+		return newError("second argument to `%s` must not be negative, got %d", name, count.Value)
+	}
+
+	switch seq := args[0].(type) {
+	case *object.Array:
+		n := int(min(count.Value, int64(len(seq.Elements))))
+		low, high := bounds(len(seq.Elements), n)
+		elements := make([]object.Object, high-low)
+		copy(elements, seq.Elements[low:high])
+		return &object.Array{Elements: elements}
+	case *object.String:
+		runes := []rune(seq.Value)
+		n := int(min(count.Value, int64(len(runes))))
+		low, high := bounds(len(runes), n)
+		return &object.String{Value: string(runes[low:high])}
+	default:
+		return newError("argument to `%s` must be ARRAY or STRING, got %s", name, args[0].Type())
+	}
+}
+
+// whileBuiltin backs takeWhile and dropWhile: part picks the elements to
+// copy out given the length n of the leading run satisfying the predicate.
+func whileBuiltin(name string, args []object.Object, part func(elements []object.Object, n int) []object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `%s` must be ARRAY, got %s", name, args[0].Type())
+	}
+
+	n := 0
+	for _, el := range arr.Elements {
+		result := applyFunction(args[1], []object.Object{el})
+		if isError(result) {
+			return result
+		}
+		if !isTruthy(result) {
+			break
+		}
+		n++
+	}
+
+	elements := part(arr.Elements, n)
+	copied := make([]object.Object, len(elements))
+	copy(copied, elements)
+	return &object.Array{Elements: copied}
+}
+
+func builtinGroupBy(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `groupBy` must be ARRAY, got %s", args[0].Type())
+	}
+
+	groups := object.NewHash()
+	for _, el := range arr.Elements {
+		key := applyFunction(args[1], []object.Object{el})
+		if isError(key) {
+			return key
+		}
+
+		hashable, ok := key.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", key.Type())
+		}
+
+		hashed := hashable.HashKey()
+		pair, ok := groups.Pairs[hashed]
+		if !ok {
+			pair = object.HashPair{Key: key, Value: &object.Array{}}
+		}
+		group := pair.Value.(*object.Array)
+		group.Elements = append(group.Elements, el)
+		groups.Set(hashed, pair)
+	}
+
+	return groups
+}
+
+// extremeBy backs minBy and maxBy: an element replaces the current best only
+// when its key compares strictly with operator, so ties keep the first one.
+func extremeBy(name, operator string, args []object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `%s` must be ARRAY, got %s", name, args[0].Type())
+	}
+
+	var best, bestKey object.Object = NULL, nil
+	for _, el := range arr.Elements {
+		key := applyFunction(args[1], []object.Object{el})
+		if isError(key) {
+			return key
+		}
+		if bestKey == nil {
+			best, bestKey = el, key
+			continue
+		}
+
+		better := evalInfixExpression(operator, key, bestKey)
+		if _, ok := better.(*object.Boolean); !ok {
+			return newError("`%s` keys are not comparable: %s and %s", name, key.Type(), bestKey.Type())
+		}
+		if better == TRUE {
+			best, bestKey = el, key
+		}
+	}
+
+	return best
+}
+
+// builtinLoop folds fn(acc, i) over 0..n-1 in a Go loop, so long iterations
+// do not grow the stack the way recursive Monkey functions do.
+func builtinLoop(args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=3", len(args))
+	}
+	n, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("first argument to `loop` must be INTEGER, got %s", args[0].Type())
+	}
+	if n.Value < 0 {
+		return newError("first argument to `loop` must not be negative, got %d", n.Value)
+	}
+
+	acc := args[1]
+	for i := int64(0); i < n.Value; i++ {
+		acc = applyFunction(args[2], []object.Object{acc, newInteger(i)})
+		if isError(acc) {
+			return acc
+		}
+	}
+
+	return acc
+}
+
+func builtinRepeat(env *object.Environment, args []object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+
+	count, ok := args[1].(*object.Integer)
+	if !ok {
+		return newError("second argument to `repeat` must be INTEGER, got %s", args[1].Type())
+	}
+	if count.Value < 0 {
+		return newError("second argument to `repeat` must not be negative, got %d", count.Value)
+	}
+	n := int(count.Value)
+
+	switch arg := args[0].(type) {
+	case *object.String:
+		size, err := resultSize("repeat", len(arg.Value), count.Value)
+		if err != nil {
+			return err
+		}
+		if err := checkAllocation(env, size); err != nil {
+			return err
+		}
+		return &object.String{Value: strings.Repeat(arg.Value, n)}
+	case *object.Array:
+		size, err := resultSize("repeat", len(arg.Elements), count.Value)
+		if err != nil {
+			return err
+		}
+		if err := checkAllocation(env, size); err != nil {
+			return err
+		}
+		elements := make([]object.Object, 0, size)
+		for i := 0; i < n && size > 0; i++ {
+			elements = append(elements, arg.Elements...)
+		}
+		return &object.Array{Elements: elements}
+	default:
+		return newError("argument to `repeat` must be STRING or ARRAY, got %s", arg.Type())
+	}
+}
+
+func builtinTimes(env *object.Environment, args []object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	n, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("first argument to `times` must be INTEGER, got %s", args[0].Type())
+	}
+	if n.Value < 0 {
+		return newError("first argument to `times` must not be negative, got %d", n.Value)
+	}
+	if n.Value > maxResultSize {
+		return newError("result of `times` too large")
+	}
+	if err := checkAllocation(env, int(n.Value)); err != nil {
+		return err
+	}
+
+	elements := []object.Object{}
+	for i := int64(0); i < n.Value; i++ {
+		result := applyFunction(args[1], []object.Object{newInteger(i)})
+		if isError(result) {
+			return result
+		}
+		elements = append(elements, result)
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinBench(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	n, ok := args[1].(*object.Integer)
+	if !ok {
+		return newError("second argument to `bench` must be INTEGER, got %s", args[1].Type())
+	}
+	if n.Value <= 0 {
+		return newError("second argument to `bench` must be positive, got %d", n.Value)
+	}
+
+	// time.Since reads the monotonic clock, so wall clock changes during
+	// the run do not skew the result
+	start := time.Now()
+	for i := int64(0); i < n.Value; i++ {
+		if result := applyFunction(args[0], []object.Object{}); isError(result) {
+			return result
+		}
+	}
+	elapsed := time.Since(start)
+
+	return &object.Float{Value: float64(elapsed.Nanoseconds()) / 1e6 / float64(n.Value)}
+}
+
+func builtinApply(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.FUNCTION_OBJ && args[0].Type() != object.BUILTIN_OBJ {
+		return newError("first argument to `apply` must be FUNCTION, got %s", args[0].Type())
+	}
+	arr, ok := args[1].(*object.Array)
+	if !ok {
+		return newError("second argument to `apply` must be ARRAY, got %s", args[1].Type())
+	}
+
+	return applyFunction(args[0], arr.Elements)
+}
+
+// hashEnumeration maps every pair of a hash, in insertion order, to an element
+// of the returned array.
+func hashEnumeration(name string, args []object.Object, element func(object.HashPair) object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	hash, ok := args[0].(*object.Hash)
+	if !ok {
+		return newError("argument to `%s` must be HASH, got %s", name, args[0].Type())
+	}
+
+	pairs := hash.OrderedPairs()
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = element(pair)
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// mutabilityBuiltin backs freeze and seal, which flag their argument in
+// place and return it.
+func mutabilityBuiltin(name string, args []object.Object, array func(*object.Array), hash func(*object.Hash)) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Array:
+		array(arg)
+	case *object.Hash:
+		hash(arg)
+	default:
+		return newError("argument to `%s` must be ARRAY or HASH, got %s", name, args[0].Type())
+	}
+	return args[0]
+}
+
+// hashKeyValue combines the type and value of key, so keys of different
+// types, like 1 and true, hash differently.
+func hashKeyValue(key object.HashKey) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key.Type))
+	h.Write(binary.LittleEndian.AppendUint64(nil, key.Value))
+	return int64(h.Sum64())
+}
+
+// BuiltinNames returns the sorted names of all builtin functions.
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func builtinBuiltins(args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+
+	names := BuiltinNames()
+	elements := make([]object.Object, len(names))
+	for i, name := range names {
+		elements[i] = &object.String{Value: name}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinHelp(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	name, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `help` must be STRING, got %s", args[0].Type())
+	}
+
+	builtin, ok := builtins[name.Value]
+	if !ok {
+		return newError("no builtin named `%s`", name.Value)
+	}
+
+	return &object.String{Value: builtin.Signature + ": " + builtin.Description}
+}
+
+// rangeCheckInterval is how many elements range builds between checks for
+// cancellation.
+const rangeCheckInterval = 1 << 12
+
+func builtinRange(env *object.Environment, args []object.Object) object.Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newError("wrong number of arguments. got=%d, want=1 to 3", len(args))
+	}
+
+	bounds := make([]int64, len(args))
+	for i, arg := range args {
+		n, ok := arg.(*object.Integer)
+		if !ok {
+			return newError("arguments to `range` must be INTEGER, got %s", arg.Type())
+		}
+		bounds[i] = n.Value
+	}
+
+	start, end, step := int64(0), bounds[0], int64(1)
+	if len(bounds) > 1 {
+		start, end = bounds[0], bounds[1]
+	}
+	if len(bounds) > 2 {
+		step = bounds[2]
+	}
+	if step == 0 {
+		return newError("step of `range` must not be zero")
+	}
+
+	count := rangeLength(start, end, step)
+	if count > math.MaxInt {
+		return newError("`range` too large: %d elements", count)
+	}
+	if err := checkAllocation(env, int(count)); err != nil {
+		return err
+	}
+
+	elements := make([]object.Object, 0, min(int(count), rangeCheckInterval))
+	for i, value := 0, start; i < int(count); i, value = i+1, value+step {
+		if i%rangeCheckInterval == 0 {
+			if err := checkRuntime(env); err != nil {
+				return err
+			}
+		}
+		elements = append(elements, newInteger(value))
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// rangeLength returns the number of elements of range(start, end, step). It
+// works on the unsigned distance, which cannot overflow, and a step pointing
+// away from end gives no elements.
+func rangeLength(start, end, step int64) uint64 {
+	var distance, stride uint64
+	switch {
+	case step > 0 && start < end:
+		distance, stride = uint64(end)-uint64(start), uint64(step)
+	case step < 0 && start > end:
+		// -step wraps for math.MinInt64, but its unsigned value is still right
+		distance, stride = uint64(start)-uint64(end), uint64(-step)
+	default:
+		return 0
+	}
+	return (distance-1)/stride + 1
+}
+
+// maxResultSize bounds the length of the strings and arrays builtins build to
+// a size worked out from their arguments, so an absurd size fails with an
+// error instead of a Go panic, whether or not MaxAllocations is set.
+const maxResultSize = 1 << 32
+
+// resultSize returns the size of count copies of length bytes or elements,
+// or an error for name when that exceeds maxResultSize. count must not be
+// negative.
+func resultSize(name string, length int, count int64) (int, *object.Error) {
+	if length > 0 && count > maxResultSize/int64(length) {
+		return 0, newError("result of `%s` too large", name)
+	}
+	return length * int(count), nil
+}
+
+// arrayAndSize validates the (arr, size) arguments shared by chunk and
+// window. Sizes beyond the array length are clamped to one past it, which
+// keeps the arithmetic in int without changing either result.
+func arrayAndSize(name string, args []object.Object) (*object.Array, int, *object.Error) {
+	if len(args) != 2 {
+		return nil, 0, newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return nil, 0, newError("first argument to `%s` must be ARRAY, got %s", name, args[0].Type())
+	}
+	size, ok := args[1].(*object.Integer)
+	if !ok {
+		return nil, 0, newError("second argument to `%s` must be INTEGER, got %s", name, args[1].Type())
+	}
+	if size.Value <= 0 {
+		return nil, 0, newError("size of `%s` must be positive, got %d", name, size.Value)
+	}
+	if size.Value > int64(len(arr.Elements)) {
+		return arr, len(arr.Elements) + 1, nil
+	}
+	return arr, int(size.Value), nil
+}
+
+func builtinChunk(env *object.Environment, args []object.Object) object.Object {
+	arr, size, err := arrayAndSize("chunk", args)
+	if err != nil {
+		return err
+	}
+	// the chunks and the elements copied into them
+	count := (len(arr.Elements) + size - 1) / size
+	if err := checkAllocation(env, count+len(arr.Elements)); err != nil {
+		return err
+	}
+
+	chunks := []object.Object{}
+	for start := 0; start < len(arr.Elements); start += size {
+		end := min(start+size, len(arr.Elements))
+		chunk := make([]object.Object, end-start)
+		copy(chunk, arr.Elements[start:end])
+		chunks = append(chunks, &object.Array{Elements: chunk})
+	}
+
+	return &object.Array{Elements: chunks}
+}
+
+func builtinWindow(env *object.Environment, args []object.Object) object.Object {
+	arr, size, err := arrayAndSize("window", args)
+	if err != nil {
+		return err
+	}
+	// the windows and the elements copied into them, which add up to far
+	// more than the array itself for large windows
+	count := max(len(arr.Elements)-size+1, 0)
+	copied, err := resultSize("window", size, int64(count))
+	if err != nil {
+		return err
+	}
+	if err := checkAllocation(env, count+copied); err != nil {
+		return err
+	}
+
+	windows := []object.Object{}
+	for start := 0; start+size <= len(arr.Elements); start++ {
+		window := make([]object.Object, size)
+		copy(window, arr.Elements[start:start+size])
+		windows = append(windows, &object.Array{Elements: window})
+	}
+
+	return &object.Array{Elements: windows}
+}
+
+func builtinZip(env *object.Environment, args []object.Object) object.Object {
+	if len(args) < 2 {
+		return newError("wrong number of arguments. got=%d, want at least 2",
+			len(args))
+	}
+
+	arrays := make([]*object.Array, len(args))
+	shortest := -1
+	for i, arg := range args {
+		arr, ok := arg.(*object.Array)
+		if !ok {
+			return newError("argument to `zip` must be ARRAY, got %s", arg.Type())
+		}
+		arrays[i] = arr
+		if shortest < 0 || len(arr.Elements) < shortest {
+			shortest = len(arr.Elements)
+		}
+	}
+
+	// the pairs and the elements copied into them
+	if err := checkAllocation(env, shortest*(len(arrays)+1)); err != nil {
+		return err
+	}
+
+	zipped := make([]object.Object, shortest)
+	for i := range zipped {
+		tuple := make([]object.Object, len(arrays))
+		for j, arr := range arrays {
+			tuple[j] = arr.Elements[i]
+		}
+		zipped[i] = &object.Array{Elements: tuple}
+	}
+	return &object.Array{Elements: zipped}
+}
+
+// floatPredicate backs isNaN and isInf. Integers are accepted and never
+// special.
+func floatPredicate(name string, args []object.Object, predicate func(float64) bool) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Integer:
+		return FALSE
+	case *object.Float:
+		return nativeBoolToBooleanObject(predicate(arg.Value))
+	default:
+		return newError("argument to `%s` must be INTEGER or FLOAT, got %s", name, args[0].Type())
+	}
 }
@@ -0,0 +1,83 @@
+package evaluator
+
+import (
+	"encoding/base64"
+	"unicode/utf8"
+
+	"monkey/src/object"
+)
+
+var bytesBuiltins = map[string]*object.Builtin{
+	"bytes": {
+		Signature:   "bytes(s) -> BYTES",
+		Description: "UTF-8 encoding of a string",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			str, err := stringArgument("bytes", args)
+			if err != nil {
+				return err
+			}
+			return &object.Bytes{Value: []byte(str.Value)}
+		},
+	},
+	"string": {
+		Signature:   "string(b) -> STRING",
+		Description: "string decoded from UTF-8 bytes",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			b, ok := args[0].(*object.Bytes)
+			if !ok {
+				return newError("argument to `string` must be BYTES, got %s", args[0].Type())
+			}
+			if !utf8.Valid(b.Value) {
+				return newError("argument to `string` is not valid UTF-8")
+			}
+			return &object.String{Value: string(b.Value)}
+		},
+	},
+	"base64Encode": {
+		Signature:   "base64Encode(data) -> STRING",
+		Description: "standard base64 encoding of bytes or of a string's UTF-8 bytes",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+
+			switch arg := args[0].(type) {
+			case *object.Bytes:
+				return &object.String{Value: base64.StdEncoding.EncodeToString(arg.Value)}
+			case *object.String:
+				return &object.String{Value: base64.StdEncoding.EncodeToString([]byte(arg.Value))}
+			default:
+				return newError("argument to `base64Encode` must be BYTES or STRING, got %s", args[0].Type())
+			}
+		},
+	},
+	"base64Decode": {
+		Signature:   "base64Decode(s) -> BYTES",
+		Description: "bytes decoded from standard base64",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			str, err := stringArgument("base64Decode", args)
+			if err != nil {
+				return err
+			}
+
+			decoded, decodeErr := base64.StdEncoding.DecodeString(str.Value)
+			if decodeErr != nil {
+				return newError("invalid base64: %s", decodeErr)
+			}
+			return &object.Bytes{Value: decoded}
+		},
+	},
+}
+
+func init() {
+	for name, builtin := range bytesBuiltins {
+		builtins[name] = builtin
+	}
+}
@@ -0,0 +1,94 @@
+package evaluator
+
+import (
+	"sort"
+
+	"monkey/src/ast"
+	"monkey/src/token"
+)
+
+// Coverage collects the source lines of the statements executed during an
+// evaluation. Enable it with SetOptions(env, Options{Coverage: cov}).
+type Coverage struct {
+	lines map[int]bool
+}
+
+func NewCoverage() *Coverage {
+	return &Coverage{lines: make(map[int]bool)}
+}
+
+func (c *Coverage) mark(node ast.Node) {
+	if line, ok := statementLine(node); ok {
+		c.lines[line] = true
+	}
+}
+
+// Covered returns the executed lines in ascending order.
+func (c *Coverage) Covered() []int {
+	lines := make([]int, 0, len(c.lines))
+	for line := range c.lines {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// Uncovered returns the lines of program that start a statement but were
+// never executed, in ascending order.
+func (c *Coverage) Uncovered(program *ast.Program) []int {
+	seen := make(map[int]bool)
+	lines := []int{}
+
+	ast.Walk(program, func(node ast.Node) bool {
+		line, ok := statementLine(node)
+		if ok && !c.lines[line] && !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+		return true
+	})
+
+	sort.Ints(lines)
+	return lines
+}
+
+// statementLine returns the line a statement starts on. Blocks are not
+// counted, they only group the statements that are.
+func statementLine(node ast.Node) (int, bool) {
+	tok, ok := statementToken(node)
+	return tok.Line, ok && tok.Line > 0
+}
+
+// statementToken returns the token a statement starts with.
+func statementToken(node ast.Node) (token.Token, bool) {
+	var tok token.Token
+
+	switch node := node.(type) {
+	case *ast.LetStatement:
+		tok = node.Token
+	case *ast.DestructuringStatement:
+		tok = node.Token
+	case *ast.ParallelLetStatement:
+		tok = node.Token
+	case *ast.ParallelAssignStatement:
+		tok = node.Names[0].Token
+	case *ast.StructStatement:
+		tok = node.Token
+	case *ast.GlobalStatement:
+		tok = node.Token
+	case *ast.ReturnStatement:
+		tok = node.Token
+	case *ast.DeferStatement:
+		tok = node.Token
+	case *ast.BreakStatement:
+		tok = node.Token
+	case *ast.ContinueStatement:
+		tok = node.Token
+	case *ast.ExpressionStatement:
+		tok = node.Token
+	default:
+		return tok, false
+	}
+
+	return tok, true
+}
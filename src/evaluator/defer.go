@@ -0,0 +1,47 @@
+package evaluator
+
+import (
+	"monkey/src/ast"
+	"monkey/src/object"
+)
+
+// deferredExpression is an expression scheduled by `defer` together with the
+// environment it appeared in.
+type deferredExpression struct {
+	expression ast.Expression
+	env        *object.Environment
+}
+
+func evalDeferStatement(node *ast.DeferStatement, env *object.Environment) object.Object {
+	rt := runtimeOf(env)
+	if len(rt.deferred) == 0 {
+		return newError("defer outside function")
+	}
+
+	frame := &rt.deferred[len(rt.deferred)-1]
+	*frame = append(*frame, deferredExpression{expression: node.Expression, env: env})
+	return nil
+}
+
+// evalFunctionBody evaluates the body of a function call in env and then
+// the expressions deferred during it, the last deferred first. They run
+// however the body ends and their values are discarded, so they cannot
+// change what the call returns. An error or exit from a deferred expression
+// is returned instead, unless the body already failed with one.
+func evalFunctionBody(body *ast.BlockStatement, env *object.Environment) object.Object {
+	rt := runtimeOf(env)
+	rt.deferred = append(rt.deferred, nil)
+	result := unwrapReturnValue(Eval(body, env))
+
+	frame := rt.deferred[len(rt.deferred)-1]
+	rt.deferred = rt.deferred[:len(rt.deferred)-1]
+
+	for i := len(frame) - 1; i >= 0; i-- {
+		evaluated := Eval(frame[i].expression, frame[i].env)
+		if isError(evaluated) && !isError(result) {
+			result = evaluated
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,143 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"monkey/src/ast"
+	"monkey/src/object"
+	"monkey/src/token"
+)
+
+var errorBuiltins = map[string]*object.Builtin{
+	"errorMessage": {
+		Signature:   "errorMessage(e) -> STRING",
+		Description: "message of an error caught by try/catch",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			caught, err := caughtErrorArgument("errorMessage", args)
+			if err != nil {
+				return err
+			}
+			return &object.String{Value: caught.Error.Message}
+		},
+	},
+	"errorLine": {
+		Signature:   "errorLine(e) -> INTEGER|NULL",
+		Description: "line of the expression a caught error was raised at, null if unknown",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			caught, err := caughtErrorArgument("errorLine", args)
+			if err != nil {
+				return err
+			}
+			return errorPosition(caught.Error.Line)
+		},
+	},
+	"errorColumn": {
+		Signature:   "errorColumn(e) -> INTEGER|NULL",
+		Description: "column of the expression a caught error was raised at, null if unknown",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			caught, err := caughtErrorArgument("errorColumn", args)
+			if err != nil {
+				return err
+			}
+			return errorPosition(caught.Error.Column)
+		},
+	},
+	"errorTrace": {
+		Signature:   "errorTrace(e) -> ARRAY",
+		Description: "calls a caught error passed through, innermost first",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			caught, err := caughtErrorArgument("errorTrace", args)
+			if err != nil {
+				return err
+			}
+			return stringArray(caught.Error.Trace)
+		},
+	},
+}
+
+func init() {
+	for name, builtin := range errorBuiltins {
+		builtins[name] = builtin
+	}
+}
+
+func caughtErrorArgument(name string, args []object.Object) (*object.CaughtError, *object.Error) {
+	if len(args) != 1 {
+		return nil, newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	caught, ok := args[0].(*object.CaughtError)
+	if !ok {
+		return nil, newError("argument to `%s` must be CAUGHT_ERROR, got %s", name, args[0].Type())
+	}
+	return caught, nil
+}
+
+func errorPosition(n int) object.Object {
+	if n == 0 {
+		return NULL
+	}
+	return newInteger(int64(n))
+}
+
+// locateError records where err was raised, node being the innermost node
+// whose evaluation failed with it that has a position.
+func locateError(err *object.Error, node ast.Node) {
+	if tok, ok := errorToken(node); ok {
+		err.Line, err.Column = tok.Line, tok.Column
+	}
+}
+
+// errorToken returns the token an error raised while evaluating node is
+// reported at: the operator of an operation, the name of an identifier and
+// so on, or else the start of the statement.
+func errorToken(node ast.Node) (token.Token, bool) {
+	var tok token.Token
+
+	switch node := node.(type) {
+	case *ast.Identifier:
+		tok = node.Token
+	case *ast.PrefixExpression:
+		tok = node.Token
+	case *ast.PostfixExpression:
+		tok = node.Token
+	case *ast.InfixExpression:
+		tok = node.Token
+	case *ast.IndexExpression:
+		tok = node.Token
+	case *ast.CallExpression:
+		tok = node.Token
+	case *ast.MemberExpression:
+		tok = node.Token
+	case *ast.AssignExpression:
+		tok = node.Token
+	case *ast.IndexAssignExpression:
+		tok = node.Token
+	case *ast.HashLiteral:
+		tok = node.Token
+	case *ast.SpreadExpression:
+		tok = node.Token
+	case *ast.ForInExpression:
+		tok = node.Token
+	default:
+		return statementToken(node)
+	}
+
+	return tok, tok.Line > 0
+}
+
+// traceCall adds the call node to the trace of err, which the call failed
+// with.
+func traceCall(err *object.Error, node *ast.CallExpression) {
+	name := "fn"
+	switch function := node.Function.(type) {
+	case *ast.Identifier:
+		name = function.Value
+	case *ast.MemberExpression:
+		name = function.Object.String() + "." + function.Property.Value
+	}
+	err.Trace = append(err.Trace, fmt.Sprintf("%s (line %d, column %d)", name, node.Token.Line, node.Token.Column))
+}
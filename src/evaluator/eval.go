@@ -0,0 +1,102 @@
+package evaluator
+
+import (
+	"strings"
+
+	"monkey/src/ast"
+	"monkey/src/lexer"
+	"monkey/src/object"
+	"monkey/src/parser"
+)
+
+// maxEvalDepth bounds how deeply code run by `eval` may call `eval` again,
+// so a string that evaluates itself fails instead of exhausting the stack.
+const maxEvalDepth = 100
+
+// environmentBuiltins need the environment they are called from. Naming one
+// of them gives a copy of the builtin bound to the environment it is named
+// in. They are added in init, as they refer back to Eval.
+var environmentBuiltins = map[string]func(env *object.Environment, args []object.Object) object.Object{}
+
+func init() {
+	builtins["eval"] = &object.Builtin{
+		Fn:          unlimited(builtinEval),
+		Signature:   "eval(code) -> ANY",
+		Description: "result of running code in the calling environment, where its let statements bind",
+		Arity:       1,
+	}
+	environmentBuiltins["eval"] = builtinEval
+
+	builtins["parse"] = &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			program, err := parseCode("parse", args)
+			if err != nil {
+				return err
+			}
+			return &object.String{Value: program.String()}
+		},
+		Signature:   "parse(code) -> STRING",
+		Description: "code parsed and printed back with explicit grouping, as the evaluator sees it",
+		Arity:       1,
+	}
+}
+
+// unlimited adapts an environment builtin to the plain builtin used where no
+// environment is at hand, e.g. when it is called through `map`. It runs in a
+// fresh environment, so no limits apply.
+func unlimited(fn func(env *object.Environment, args []object.Object) object.Object) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		return fn(object.NewEnvironment(), args)
+	}
+}
+
+func bindBuiltin(builtin *object.Builtin, fn func(env *object.Environment, args []object.Object) object.Object, env *object.Environment) *object.Builtin {
+	bound := *builtin
+	bound.Fn = func(args ...object.Object) object.Object {
+		return fn(env, args)
+	}
+	return &bound
+}
+
+// builtinEval runs code in env. It is evaluated like any other code in env,
+// so the step and allocation limits set for env apply to it as well, and its
+// macros are defined in env and expanded first, as in the REPL.
+func builtinEval(env *object.Environment, args []object.Object) object.Object {
+	program, err := parseCode("eval", args)
+	if err != nil {
+		return err
+	}
+
+	rt := runtimeOf(env)
+	if rt.evalDepth >= maxEvalDepth {
+		return newError("`eval` nested too deeply")
+	}
+	rt.evalDepth++
+	defer func() { rt.evalDepth-- }()
+
+	DefineMacros(program, env)
+	expanded, failed := ExpandMacros(program, env)
+	if failed != nil {
+		return failed
+	}
+
+	result := Eval(expanded, env)
+	if result == nil {
+		return NULL
+	}
+	return result
+}
+
+func parseCode(name string, args []object.Object) (*ast.Program, *object.Error) {
+	code, err := stringArgument(name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser.New(lexer.New(code.Value))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, newError("cannot parse: %s", strings.Join(p.Errors(), "; "))
+	}
+	return program, nil
+}
@@ -2,31 +2,76 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
 	"monkey/src/ast"
 	"monkey/src/object"
 )
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	result := evalNode(node, env)
+	if err, ok := result.(*object.Error); ok && err.Line == 0 {
+		locateError(err, node)
+	}
+	return result
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.Object {
+	if err := visit(env, node); err != nil {
+		return err
+	}
+
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
 	case *ast.IntegerLiteral:
-		return &object.Integer{Value: node.Value}
+		return newInteger(node.Value)
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
+	case *ast.NullLiteral:
+		return NULL
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 	case *ast.StringLiteral:
-		return &object.String{Value: node.Value}
+		return allocate(env, &object.String{Value: node.Value})
 	case *ast.LetStatement:
 		val := Eval(node.Value, env)
 		if isError(val) {
 			return val
 		}
 		env.Set(node.Name.Value, val)
+	case *ast.DestructuringStatement:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		if err := destructure(node.Names, val, env); err != nil {
+			return err
+		}
+	case *ast.ParallelLetStatement:
+		vals, err := evalParallelValues(node.Values, env)
+		if err != nil {
+			return err
+		}
+		for i, name := range node.Names {
+			env.Set(name.Value, vals[i])
+		}
+	case *ast.ParallelAssignStatement:
+		return evalParallelAssignStatement(node, env)
+	case *ast.StructStatement:
+		return evalStructStatement(node, env)
+	case *ast.GlobalStatement:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		env.SetGlobal(node.Name.Value, val)
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
 		if isError(right) {
@@ -44,7 +89,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return right
 		}
 
-		return evalInfixExpression(node.Operator, left, right)
+		return allocate(env, evalInfixExpression(node.Operator, left, right))
 	case *ast.IndexExpression:
 		left := Eval(node.Left, env)
 		if isError(left) {
@@ -57,8 +102,37 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalIndexExpression(left, index)
 	case *ast.BlockStatement:
 		return evalBlockStatement(node, env)
+	case *ast.AssignExpression:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		if _, ok := env.Assign(node.Name.Value, val); !ok {
+			return newError("identifier not found: `%s`", node.Name.Value)
+		}
+		return val
+	case *ast.PostfixExpression:
+		return evalPostfixExpression(node, env)
+	case *ast.DeferStatement:
+		return evalDeferStatement(node, env)
+	case *ast.BreakStatement:
+		return BREAK
+	case *ast.ContinueStatement:
+		return CONTINUE
+	case *ast.RepeatExpression:
+		return evalPostConditionLoop(node.Body, node.Condition, true, env)
+	case *ast.ForInExpression:
+		return evalForInExpression(node, env)
+	case *ast.DoWhileExpression:
+		return evalPostConditionLoop(node.Body, node.Condition, false, env)
+	case *ast.IndexAssignExpression:
+		return evalIndexAssignExpression(node, env)
+	case *ast.MemberExpression:
+		return evalMemberExpression(node, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
@@ -67,6 +141,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			Body:       body,
 			Env:        env,
 		}
+	case *ast.MacroLiteral:
+		return newError("macros can only be defined by top-level let statements")
 	case *ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
 		if isError(val) {
@@ -74,41 +150,109 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 		return &object.ReturnValue{Value: val}
 	case *ast.CallExpression:
+		if isCallTo(node, "quote") {
+			return evalQuoteCall(node, env)
+		}
+
 		function := Eval(node.Function, env)
 		if isError(function) {
 			return function
 		}
 
-		args := evalExpression(node.Arguments, env)
+		args := evalCallArguments(function, node.Arguments, env)
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(function, args)
+
+		result := applyFunction(function, args)
+		if err, ok := result.(*object.Error); ok {
+			traceCall(err, node)
+		}
+		if function.Type() == object.BUILTIN_OBJ {
+			// builtins have no access to env, their results are charged here
+			return allocate(env, result)
+		}
+		return result
 	case *ast.ArrayLiteral:
 		elements := evalExpression(node.Elements, env)
 		if len(elements) == 1 && isError(elements[0]) {
 			return elements[0]
 		}
 
-		return &object.Array{Elements: elements}
+		return allocate(env, &object.Array{Elements: elements})
 	case *ast.HashLiteral:
-		return evalHashLiteral(node, env)
+		return allocate(env, evalHashLiteral(node, env))
+	case *ast.SpreadExpression:
+		return newError("spread syntax is only allowed in array literals and call arguments")
 	default:
 		panic(fmt.Sprintf("unexpected ast.Node: %#v", node))
 	}
 	return nil
 }
 
+// destructure binds the elements of val to names, which must match them in
+// number.
+func destructure(names []*ast.Identifier, val object.Object, env *object.Environment) *object.Error {
+	arr, ok := val.(*object.Array)
+	if !ok {
+		return newError("cannot destructure %s, expected ARRAY", val.Type())
+	}
+	if len(arr.Elements) != len(names) {
+		return newError("cannot destructure %d elements into %d names", len(arr.Elements), len(names))
+	}
+
+	for i, name := range names {
+		env.Set(name.Value, arr.Elements[i])
+	}
+	return nil
+}
+
+// evalParallelValues evaluates the values of a parallel let or assignment,
+// all of them before anything is bound so `a, b = b, a` swaps.
+func evalParallelValues(values []ast.Expression, env *object.Environment) ([]object.Object, object.Object) {
+	vals := make([]object.Object, len(values))
+	for i, value := range values {
+		vals[i] = Eval(value, env)
+		if isError(vals[i]) {
+			return nil, vals[i]
+		}
+	}
+	return vals, nil
+}
+
+// evalParallelAssignStatement checks that every name is bound before
+// assigning any, so a failing assignment leaves all variables unchanged.
+func evalParallelAssignStatement(node *ast.ParallelAssignStatement, env *object.Environment) object.Object {
+	vals, err := evalParallelValues(node.Values, env)
+	if err != nil {
+		return err
+	}
+	for _, name := range node.Names {
+		if _, ok := env.Get(name.Value); !ok {
+			return newError("identifier not found: `%s`", name.Value)
+		}
+	}
+
+	for i, name := range node.Names {
+		env.Assign(name.Value, vals[i])
+	}
+	return nil
+}
+
 func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	var result object.Object
 
 	for _, statement := range program.Statements {
+		if err := checkRuntime(env); err != nil {
+			return err
+		}
+
 		result = Eval(statement, env)
 
 		switch result := result.(type) {
 		case *object.ReturnValue:
 			return result.Value
-		case *object.Error:
+		case *object.Error, *object.Exit:
 			return result
 		}
 	}
@@ -120,6 +264,19 @@ func evalExpression(exps []ast.Expression, env *object.Environment) []object.Obj
 	var result []object.Object
 
 	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			evaluated := Eval(spread.Value, env)
+			if isError(evaluated) {
+				return []object.Object{evaluated}
+			}
+			arr, ok := evaluated.(*object.Array)
+			if !ok {
+				return []object.Object{newError("cannot spread %s, expected ARRAY", evaluated.Type())}
+			}
+			result = append(result, arr.Elements...)
+			continue
+		}
+
 		evaluated := Eval(e, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
@@ -130,12 +287,110 @@ func evalExpression(exps []ast.Expression, env *object.Environment) []object.Obj
 	return result
 }
 
+// evalCallArguments evaluates the arguments of a call. Keyword arguments are
+// matched against the parameter names of fn and placed at their position, so
+// the result can be handed to applyFunction like a purely positional call.
+func evalCallArguments(fn object.Object, exps []ast.Expression, env *object.Environment) []object.Object {
+	positional := []ast.Expression{}
+	keywords := []*ast.KeywordArgument{}
+	for _, e := range exps {
+		if kw, ok := e.(*ast.KeywordArgument); ok {
+			keywords = append(keywords, kw)
+		} else {
+			positional = append(positional, e)
+		}
+	}
+
+	args := evalExpression(positional, env)
+	if len(keywords) == 0 || (len(args) == 1 && isError(args[0])) {
+		return args
+	}
+
+	params, ok := parameterNames(fn)
+	if !ok {
+		return []object.Object{newError("keyword arguments not supported: %s", fn.Type())}
+	}
+	if len(args) > len(params) {
+		return []object.Object{newError("wrong number of arguments. got=%d, want=%d",
+			len(args)+len(keywords), len(params))}
+	}
+
+	bound := make([]object.Object, len(params))
+	copy(bound, args)
+
+	for _, kw := range keywords {
+		idx := -1
+		for i, param := range params {
+			if param == kw.Name.Value {
+				idx = i
+				break
+			}
+		}
+
+		switch {
+		case idx < 0:
+			return []object.Object{newError("unexpected keyword argument: `%s`", kw.Name.Value)}
+		case idx < len(args):
+			return []object.Object{newError("multiple values for argument: `%s`", kw.Name.Value)}
+		case bound[idx] != nil:
+			return []object.Object{newError("duplicate keyword argument: `%s`", kw.Name.Value)}
+		}
+
+		val := Eval(kw.Value, env)
+		if isError(val) {
+			return []object.Object{val}
+		}
+		bound[idx] = val
+	}
+
+	for i, val := range bound {
+		if val == nil {
+			return []object.Object{newError("missing argument: `%s`", params[i])}
+		}
+	}
+
+	return bound
+}
+
+// parameterNames returns the names arguments of fn can be passed by, a struct
+// takes its fields.
+func parameterNames(fn object.Object) ([]string, bool) {
+	var params []*ast.Identifier
+	switch fn := fn.(type) {
+	case *object.Function:
+		params = fn.Parameters
+	case *object.BoundMethod:
+		params = fn.Method.Parameters
+	case *object.Struct:
+		return fn.Fields, true
+	default:
+		return nil, false
+	}
+
+	names := make([]string, len(params))
+	for i, param := range params {
+		names[i] = param.Value
+	}
+	return names, true
+}
+
 func applyFunction(fn object.Object, args []object.Object) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
+		if len(args) != len(fn.Parameters) {
+			return newError("wrong number of arguments. got=%d, want=%d", len(args), len(fn.Parameters))
+		}
 		extendedEnv := extendFunctionEnv(fn, args)
-		evaluated := Eval(fn.Body, extendedEnv)
-		return unwrapReturnValue(evaluated)
+		return evalFunctionBody(fn.Body, extendedEnv)
+	case *object.BoundMethod:
+		if len(args) != len(fn.Method.Parameters) {
+			return newError("wrong number of arguments. got=%d, want=%d", len(args), len(fn.Method.Parameters))
+		}
+		extendedEnv := extendFunctionEnv(fn.Method, args)
+		extendedEnv.Set("self", fn.Receiver)
+		return evalFunctionBody(fn.Method.Body, extendedEnv)
+	case *object.Struct:
+		return newInstance(fn, args)
 	case *object.Builtin:
 		return fn.Fn(args...)
 	default:
@@ -164,11 +419,16 @@ func unwrapReturnValue(obj object.Object) object.Object {
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
 	var result object.Object
 	for _, statement := range block.Statements {
+		if err := checkRuntime(env); err != nil {
+			return err
+		}
+
 		result = Eval(statement, env)
 
 		if result != nil {
 			rt := result.Type()
-			if rt == object.RETURN_OBJ || rt == object.ERROR_OBJ {
+			if rt == object.RETURN_OBJ || rt == object.ERROR_OBJ || rt == object.EXIT_OBJ ||
+				rt == object.BREAK_OBJ || rt == object.CONTINUE_OBJ {
 				return result
 			}
 		}
@@ -182,7 +442,13 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 		return val
 	}
 
-	if builtin, ok := builtins[node.Value]; ok {
+	if builtin, ok := builtins[node.Value]; ok && !builtinDisabled(env, node.Value) {
+		if builtin.System && !systemAllowed(env) {
+			return newError("`%s` requires system access", node.Value)
+		}
+		if fn, ok := environmentBuiltins[node.Value]; ok {
+			return bindBuiltin(builtin, fn, env)
+		}
 		return builtin
 	}
 
@@ -225,11 +491,83 @@ func evalIndexExpression(left, index object.Object) object.Object {
 		return evalArrayIndexExpression(left, index)
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
+	case left.Type() == object.BYTES_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalBytesIndexExpression(left, index)
 	default:
 		return newError("index operator not supported: %s", left.Type())
 	}
 }
 
+// evalIndexAssignExpression stores the value in the container the target
+// indexes. Containers are shared by reference, so for `a[0][1] = v` the
+// inner array is read through a[0] and updated in place.
+func evalIndexAssignExpression(node *ast.IndexAssignExpression, env *object.Environment) object.Object {
+	container := Eval(node.Target.Left, env)
+	if isError(container) {
+		return container
+	}
+	index := Eval(node.Target.Index, env)
+	if isError(index) {
+		return index
+	}
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	switch container := container.(type) {
+	case *object.Array:
+		if container.Frozen {
+			return newError("cannot assign to frozen ARRAY")
+		}
+		idx, ok := index.(*object.Integer)
+		if !ok {
+			return newError("array index must be INTEGER, got %s", index.Type())
+		}
+		if idx.Value < 0 || idx.Value >= int64(len(container.Elements)) {
+			return newError("index out of range: %d", idx.Value)
+		}
+		container.Elements[idx.Value] = val
+	case *object.Hash:
+		if container.Frozen {
+			return newError("cannot assign to frozen HASH")
+		}
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", index.Type())
+		}
+		if _, exists := container.Pairs[key.HashKey()]; !exists && container.Sealed {
+			return newError("cannot add key %s to sealed HASH", index.Inspect())
+		}
+		container.Set(key.HashKey(), object.HashPair{Key: index, Value: val})
+	default:
+		return newError("index assignment not supported: %s", container.Type())
+	}
+
+	return val
+}
+
+// evalPostfixExpression increments or decrements an integer binding and
+// returns the value it had before.
+func evalPostfixExpression(node *ast.PostfixExpression, env *object.Environment) object.Object {
+	val, ok := env.Get(node.Name.Value)
+	if !ok {
+		return newError("identifier not found: `%s`", node.Name.Value)
+	}
+	integer, ok := val.(*object.Integer)
+	if !ok {
+		return newError("unknown operation: %s%s", val.Type(), node.Operator)
+	}
+
+	delta := int64(1)
+	if node.Operator == "--" {
+		delta = -1
+	}
+	env.Assign(node.Name.Value, newInteger(integer.Value+delta))
+
+	return integer
+}
+
 func evalHashIndexExpression(hash, index object.Object) object.Object {
 	hashObject := hash.(*object.Hash)
 
@@ -258,10 +596,20 @@ func evalArrayIndexExpression(array, index object.Object) object.Object {
 	return arrayObject.Elements[idx]
 }
 
+func evalBytesIndexExpression(b, index object.Object) object.Object {
+	value := b.(*object.Bytes).Value
+	idx := index.(*object.Integer).Value
+
+	if idx < 0 || idx >= int64(len(value)) {
+		return NULL
+	}
+	return newInteger(int64(value[idx]))
+}
+
 func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := object.NewHash()
 
-	for keyNode, valNode := range node.Pairs {
+	for _, keyNode := range node.Keys {
 		key := Eval(keyNode, env)
 		if isError(key) {
 			return key
@@ -271,25 +619,39 @@ func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Obje
 		if !ok {
 			return newError("unuseable as a hashkey: %s", key.Type())
 		}
+		if _, exists := hash.Pairs[hashKey.HashKey()]; exists {
+			return newError("duplicate hash key: %s", quoteKey(key))
+		}
 
-		value := Eval(valNode, env)
+		value := Eval(node.Pairs[keyNode], env)
 		if isError(value) {
 			return value
 		}
 
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		hash.Set(hashKey.HashKey(), object.HashPair{Key: key, Value: value})
 	}
 
-	return &object.Hash{Pairs: pairs}
+	return hash
+}
+
+// quoteKey renders a hash key for messages, quoting strings so "1" and 1
+// are told apart.
+func quoteKey(key object.Object) string {
+	if str, ok := key.(*object.String); ok {
+		return strconv.Quote(str.Value)
+	}
+	return key.Inspect()
 }
 
 func evalMinusOperatorExpression(exp object.Object) object.Object {
-	if exp.Type() != object.INTEGER_OBJ {
+	switch exp := exp.(type) {
+	case *object.Integer:
+		return newInteger(-exp.Value)
+	case *object.Float:
+		return &object.Float{Value: -exp.Value}
+	default:
 		return newError("unknown operation: -%s", exp.Type())
 	}
-	value := exp.(*object.Integer).Value
-	return &object.Integer{Value: -value}
 }
 
 func evalBangOperatorExpression(exp object.Object) object.Object {
@@ -306,9 +668,23 @@ func evalBangOperatorExpression(exp object.Object) object.Object {
 }
 
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
+	if method, ok := operatorMethod(operator, left); ok {
+		return evalOverloadedInfixExpression(operator, method, right)
+	}
+
 	switch {
+	case operator == "in":
+		return evalInExpression(left, right)
+	case operator == "%" && left.Type() == object.STRING_OBJ:
+		return evalFormatExpression(left, right)
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
+	case isNumber(left) && isNumber(right):
+		return evalFloatInfixExpression(operator, left, right)
+	case (left == NULL || right == NULL) && operator == "==":
+		return nativeBoolToBooleanObject(left == right)
+	case (left == NULL || right == NULL) && operator == "!=":
+		return nativeBoolToBooleanObject(left != right)
 	case left.Type() != right.Type():
 		return newError("type missmatch: %s %s %s", left.Type(), operator, right.Type())
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
@@ -322,19 +698,57 @@ func evalInfixExpression(operator string, left, right object.Object) object.Obje
 	}
 }
 
+func evalInExpression(left, right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Hash:
+		key, ok := left.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", left.Type())
+		}
+		_, ok = right.Pairs[key.HashKey()]
+		return nativeBoolToBooleanObject(ok)
+	case *object.Set:
+		key, ok := left.(object.Hashable)
+		if !ok {
+			return FALSE
+		}
+		_, ok = right.Elements[key.HashKey()]
+		return nativeBoolToBooleanObject(ok)
+	case *object.Array:
+		for _, el := range right.Elements {
+			if object.Equal(left, el) {
+				return TRUE
+			}
+		}
+		return FALSE
+	case *object.String:
+		str, ok := left.(*object.String)
+		if !ok {
+			return newError("type missmatch: %s in %s", left.Type(), right.Type())
+		}
+		return nativeBoolToBooleanObject(strings.Contains(right.Value, str.Value))
+	default:
+		return newError("type missmatch: %s in %s", left.Type(), right.Type())
+	}
+}
+
 func newError(format string, a ...interface{}) *object.Error {
 	return &object.Error{
 		Message: fmt.Sprintf(format, a...),
 	}
 }
 
+// isError reports whether obj stops the evaluation. Besides errors that is an
+// exit requested by the script, so it travels up the same way.
 func isError(obj object.Object) bool {
 	if obj != nil {
-		return obj.Type() == object.ERROR_OBJ
+		return obj.Type() == object.ERROR_OBJ || obj.Type() == object.EXIT_OBJ
 	}
 	return false
 }
 
+// evalIfExpression runs the chosen branch in its own scope: `let` inside it
+// does not leak, while `=` still updates the variables around it.
 func evalIfExpression(node *ast.IfExpression, env *object.Environment) object.Object {
 	condition := Eval(node.Condition, env)
 	if isError(condition) {
@@ -342,12 +756,46 @@ func evalIfExpression(node *ast.IfExpression, env *object.Environment) object.Ob
 	}
 
 	if isTruthy(condition) {
-		return Eval(node.Consequence, env)
-	} else if node.Alternative != nil {
-		return Eval(node.Alternative, env)
-	} else {
-		return NULL
+		return Eval(node.Consequence, object.NewEnclosedEnvironment(env))
+	}
+
+	for _, branch := range node.ElseIfs {
+		condition := Eval(branch.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if isTruthy(condition) {
+			return Eval(branch.Consequence, object.NewEnclosedEnvironment(env))
+		}
 	}
+
+	if node.Alternative != nil {
+		return Eval(node.Alternative, object.NewEnclosedEnvironment(env))
+	}
+	return NULL
+}
+
+// evalTryExpression runs the handler when the block fails with an error that
+// is not fatal. Both blocks get their own scope, the handler's binds the
+// error message to the catch parameter and, with a second parameter, the
+// error itself to that one.
+func evalTryExpression(node *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(node.Block, object.NewEnclosedEnvironment(env))
+
+	err, ok := result.(*object.Error)
+	if !ok || err.Fatal {
+		return result
+	}
+
+	handlerEnv := object.NewEnclosedEnvironment(env)
+	if node.Param != nil {
+		handlerEnv.Set(node.Param.Value, &object.String{Value: err.Message})
+	}
+	if node.ErrorParam != nil {
+		handlerEnv.Set(node.ErrorParam.Value, &object.CaughtError{Error: err})
+	}
+
+	return Eval(node.Handler, handlerEnv)
 }
 
 func isTruthy(obj object.Object) bool {
@@ -379,13 +827,18 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 
 	switch operator {
 	case "-":
-		return &object.Integer{Value: leftVal - rightVal}
+		return newInteger(leftVal - rightVal)
 	case "+":
-		return &object.Integer{Value: leftVal + rightVal}
+		return newInteger(leftVal + rightVal)
 	case "*":
-		return &object.Integer{Value: leftVal * rightVal}
+		return newInteger(leftVal * rightVal)
 	case "/":
-		return &object.Integer{Value: leftVal / rightVal}
+		return newInteger(leftVal / rightVal)
+	case "%":
+		if rightVal == 0 {
+			return newError("division by zero: %d %% %d", leftVal, rightVal)
+		}
+		return newInteger(leftVal % rightVal)
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
@@ -399,8 +852,77 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	}
 }
 
+func isNumber(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.FLOAT_OBJ
+}
+
+func toFloat(obj object.Object) float64 {
+	if i, ok := obj.(*object.Integer); ok {
+		return float64(i.Value)
+	}
+	return obj.(*object.Float).Value
+}
+
+// evalFloatInfixExpression handles arithmetic where at least one operand is a
+// float, the other one is converted. It follows IEEE 754 rather than
+// reporting errors: dividing by zero gives Infinity, -Infinity or NaN, and
+// NaN compares unequal to everything, itself included.
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := toFloat(left)
+	rightVal := toFloat(right)
+
+	switch operator {
+	case "-":
+		return &object.Float{Value: leftVal - rightVal}
+	case "+":
+		return &object.Float{Value: leftVal + rightVal}
+	case "*":
+		return &object.Float{Value: leftVal * rightVal}
+	case "/":
+		return &object.Float{Value: leftVal / rightVal}
+	case "%":
+		return &object.Float{Value: math.Mod(leftVal, rightVal)}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newError("unknown operation: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// NULL, TRUE and FALSE are the only null and boolean values the evaluator
+// produces, every expression and builtin yielding one of them returns these
+// instances. They can therefore be compared by identity.
 var (
 	NULL  = &object.Null{}
 	TRUE  = &object.Boolean{Value: true}
 	FALSE = &object.Boolean{Value: false}
 )
+
+const (
+	minSmallInteger = -128
+	maxSmallInteger = 256
+)
+
+// smallIntegers interns the integers loop counters and indices mostly
+// consist of, so producing them does not allocate. Integers are never
+// mutated, which makes sharing them unobservable.
+var smallIntegers = func() []*object.Integer {
+	integers := make([]*object.Integer, maxSmallInteger-minSmallInteger+1)
+	for i := range integers {
+		integers[i] = &object.Integer{Value: int64(i + minSmallInteger)}
+	}
+	return integers
+}()
+
+func newInteger(value int64) *object.Integer {
+	if value >= minSmallInteger && value <= maxSmallInteger {
+		return smallIntegers[value-minSmallInteger]
+	}
+	return &object.Integer{Value: value}
+}
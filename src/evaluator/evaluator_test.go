@@ -1,6 +1,10 @@
 package evaluator
 
 import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
 	"testing"
 
 	"monkey/src/lexer"
@@ -143,6 +147,10 @@ func TestIfElseExpression(t *testing.T) {
 		{"if (1 > 2) { 10 }", nil},
 		{"if (1 > 2) { 10 } else { 20 }", 20},
 		{"if (1 < 2) { 10 } else { 20 }", 10},
+		{"if (1 > 2) { 10 } else if (2 > 1) { 20 } else { 30 }", 20},
+		{"if (1 > 2) { 10 } else if (2 > 3) { 20 } else { 30 }", 30},
+		{"if (1 > 2) { 10 } else if (2 > 3) { 20 }", nil},
+		{"if (false) { 1 } else if (false) { 2 } else if (true) { 3 } else { 4 }", 3},
 	}
 
 	for _, tt := range tests {
@@ -383,6 +391,9 @@ func TestBuiltinFunctions(t *testing.T) {
 		{`len("")`, 0},
 		{`len("four")`, 4},
 		{`len("hello worlds")`, 12},
+		{`len([1, 2, 3])`, 3},
+		{`len({"a": 1, "b": 2})`, 2},
+		{`len({})`, 0},
 		{`len(1)`, "argument to `len` not supported: INTEGER"},
 		{`len(1,2)`, "wrong number of arguments. Got: 2, take: 1"},
 	}
@@ -565,3 +576,2023 @@ func TestHandleHashExpression(t *testing.T) {
 		}
 	}
 }
+
+func TestInOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"foo" in {"foo": 1}`, true},
+		{`"bar" in {"foo": 1}`, false},
+		{`1 in {1: "one", 2: "two"}`, true},
+		{`2 in [1, 2, 3]`, true},
+		{`4 in [1, 2, 3]`, false},
+		{`[1, 2] in [[1, 2], [3]]`, true},
+		{`{"a": 1} in [{"a": 1}]`, true},
+		{`"ell" in "hello"`, true},
+		{`"xyz" in "hello"`, false},
+		{`1 in 2`, "type missmatch: INTEGER in INTEGER"},
+		{`1 in "hello"`, "type missmatch: INTEGER in STRING"},
+		{`[1] in {"a": 1}`, "unusable as hash key: ARRAY"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestAnyAllBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`any([1, 2, 3], fn(x) { x > 2 })`, true},
+		{`any([1, 2, 3], fn(x) { x > 3 })`, false},
+		{`any([], fn(x) { true })`, false},
+		{`all([1, 2, 3], fn(x) { x > 0 })`, true},
+		{`all([1, 2, 3], fn(x) { x > 1 })`, false},
+		{`all([], fn(x) { false })`, true},
+		{`any([1, 2], fn(x) { x + true })`, "type missmatch: INTEGER + BOOLEAN"},
+		{`all([1, 2], fn(x) { x + true })`, "type missmatch: INTEGER + BOOLEAN"},
+		{`any([1, true], fn(x) { x == 1 })`, true},
+		{`all([1, true], fn(x) { x != 1 })`, false},
+		{`any(1, fn(x) { x })`, "argument to `any` must be ARRAY, got INTEGER"},
+		{`all([1])`, "wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func testErrorObject(t *testing.T, obj object.Object, expected string) bool {
+	errorObject, ok := obj.(*object.Error)
+	if !ok {
+		t.Errorf("Expected Error Object, got: %T (%+v)", obj, obj)
+		return false
+	}
+
+	if errorObject.Message != expected {
+		t.Errorf("wrong error message, expected: %s, got: %s", expected, errorObject.Message)
+		return false
+	}
+
+	return true
+}
+
+func TestZipBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`zip([1, 2, 3], ["a", "b"])`, `[[1, "a"], [2, "b"]]`},
+		{`zip([1, 2], [3, 4], [5, 6])`, "[[1, 3, 5], [2, 4, 6]]"},
+		{`zip([], [1, 2])`, "[]"},
+		{`zip([1], 2)`, "ERROR: argument to `zip` must be ARRAY, got INTEGER"},
+		{`zip([1])`, "ERROR: wrong number of arguments. got=1, want at least 2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func testInspect(t *testing.T, obj object.Object, expected string) bool {
+	if obj == nil {
+		t.Errorf("Object is nil, expected: %s", expected)
+		return false
+	}
+
+	if obj.Inspect() != expected {
+		t.Errorf("Object inspect wrong, expected: %s, got: %s", expected, obj.Inspect())
+		return false
+	}
+
+	return true
+}
+
+func TestTakeDropBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`take([1, 2, 3], 2)`, "[1, 2]"},
+		{`take([1, 2, 3], 0)`, "[]"},
+		{`take([1, 2, 3], 10)`, "[1, 2, 3]"},
+		{`drop([1, 2, 3], 1)`, "[2, 3]"},
+		{`drop([1, 2, 3], 0)`, "[1, 2, 3]"},
+		{`drop([1, 2, 3], 10)`, "[]"},
+		{`take("hello", 3)`, "hel"},
+		{`drop("hello", 3)`, "lo"},
+		{`take([1], -1)`, "ERROR: second argument to `take` must not be negative, got -1"},
+		{`drop([1], "a")`, "ERROR: second argument to `drop` must be INTEGER, got STRING"},
+		{`take(1, 1)`, "ERROR: argument to `take` must be ARRAY or STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestUniqueBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`unique([1, 2, 1, 3, 2])`, "[1, 2, 3]"},
+		{`unique(["b", "a", "b"])`, `["b", "a"]`},
+		{`unique([1, true, 1, true, false])`, "[1, true, false]"},
+		{`unique([[1], [2], [1]])`, "[[1], [2]]"},
+		{`unique([])`, "[]"},
+		{`unique("abc")`, "ERROR: argument to `unique` must be ARRAY, got STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestGroupByBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let g = groupBy([1, 2, 3, 4], fn(x) { x > 2 }); g[true]`, "[3, 4]"},
+		{`let g = groupBy([1, 2, 3, 4], fn(x) { x > 2 }); g[false]`, "[1, 2]"},
+		{`let g = groupBy(["a", "bb", "c"], fn(s) { len(s) }); g[1]`, `["a", "c"]`},
+		{`groupBy([], fn(x) { x })`, "{}"},
+		{`groupBy([1], fn(x) { [x] })`, "ERROR: unusable as hash key: ARRAY"},
+		{`groupBy([1], fn(x) { x + true })`, "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{`groupBy(1, fn(x) { x })`, "ERROR: argument to `groupBy` must be ARRAY, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	input := `let λ = fn(café) { café * 2 }; let π = 3; λ(π)`
+
+	testIntegerObject(t, testEval(input), 6)
+}
+
+func TestRepeatBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`repeat("ab", 3)`, "ababab"},
+		{`repeat("ab", 0)`, ""},
+		{`repeat([1, 2], 2)`, "[1, 2, 1, 2]"},
+		{`repeat([1], 0)`, "[]"},
+		{`repeat("ab", -1)`, "ERROR: second argument to `repeat` must not be negative, got -1"},
+		{`repeat("ab", "c")`, "ERROR: second argument to `repeat` must be INTEGER, got STRING"},
+		{`repeat(1, 2)`, "ERROR: argument to `repeat` must be STRING or ARRAY, got INTEGER"},
+		{`repeat("ab", 4611686018427387904)`, "ERROR: result of `repeat` too large"},
+		{`repeat([1], 4611686018427387904)`, "ERROR: result of `repeat` too large"},
+		{`repeat([1, 2], 9223372036854775807)`, "ERROR: result of `repeat` too large"},
+		{`repeat([], 9223372036854775807)`, "[]"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFormatOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"x = %d, y = %s" % [1, "two"]`, "x = 1, y = two"},
+		{`"%v and %v" % [[1, 2], true]`, "[1, 2] and true"},
+		{`"100%%" % []`, "100%"},
+		{`"hello %s" % "world"`, "hello world"},
+		{`let x = 5; "x is %d" % x`, "x is 5"},
+		{`"%d %d" % [1]`, "ERROR: not enough arguments for format string: got 1"},
+		{`"%d" % [1, 2]`, "ERROR: too many arguments for format string: want 1, got 2"},
+		{`"%d" % ["a"]`, "ERROR: %d expects INTEGER, got STRING"},
+		{`"%q" % [1]`, "ERROR: unknown format verb: %q"},
+		{`"50%" % []`, "ERROR: format string ends with a lone %"},
+		{`7 % 3`, "1"},
+		{`-7 % 3`, "-1"},
+		{`7 % 0`, "ERROR: division by zero: 7 % 0"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestKeywordArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let f = fn(a, b) { a - b }; f(b = 1, a = 10)`, "9"},
+		{`let f = fn(a, b) { a - b }; f(10, b = 1)`, "9"},
+		{`let f = fn(a, b, c) { [a, b, c] }; f(1, c = 3, b = 2)`, "[1, 2, 3]"},
+		{`let f = fn(a) { a }; f(b = 1)`, "ERROR: unexpected keyword argument: `b`"},
+		{`let f = fn(a, b) { a }; f(a = 1, a = 2)`, "ERROR: duplicate keyword argument: `a`"},
+		{`let f = fn(a, b) { a }; f(1, a = 2)`, "ERROR: multiple values for argument: `a`"},
+		{`let f = fn(a, b) { a }; f(a = 1)`, "ERROR: missing argument: `b`"},
+		{`let f = fn(a) { a }; f(1, 2, a = 3)`, "ERROR: wrong number of arguments. got=3, want=1"},
+		{`len(x = "abc")`, "ERROR: keyword arguments not supported: BUILTIN"},
+		{`let f = fn(a) { a }; f(a = 1 + true)`, "ERROR: type missmatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestGlobalStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let x = 1; let f = fn() { global x = 2; }; f(); x", 2},
+		{"let f = fn() { global y = 5; }; f(); y", 5},
+		{"let inc = fn() { global n = n + 1; }; let n = 0; inc(); inc(); n", 2},
+		{"let f = fn() { fn() { global z = 3; } }; f()(); z", 3},
+		// a local binding keeps shadowing the global one inside its scope
+		{"let x = 1; let f = fn() { let x = 10; global x = 2; x }; f()", 10},
+		{"let x = 1; let f = fn() { let x = 10; global x = 2; x }; f(); x", 2},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestLoopBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`loop(5, 0, fn(acc, i) { acc + i })`, "10"},
+		{`loop(0, 42, fn(acc, i) { acc + i })`, "42"},
+		{`loop(3, [], fn(acc, i) { push(acc, i * i) })`, "[0, 1, 4]"},
+		{`loop(100000, 0, fn(acc, i) { acc + 1 })`, "100000"},
+		{`loop(3, 0, fn(acc, i) { acc + true })`, "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{`loop(-1, 0, fn(acc, i) { acc })`, "ERROR: first argument to `loop` must not be negative, got -1"},
+		{`loop("a", 0, fn(acc, i) { acc })`, "ERROR: first argument to `loop` must be INTEGER, got STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestRawStringLiteral(t *testing.T) {
+	input := "`C:\\new\\table` + \"\\tend\""
+
+	evaluated := testEval(input)
+	str, ok := evaluated.(*object.String)
+	if !ok {
+		t.Fatalf("evaluated is not String, got: %T (%+v)", evaluated, evaluated)
+	}
+
+	if str.Value != "C:\\new\\table\tend" {
+		t.Fatalf("str.Value wrong, got: %q", str.Value)
+	}
+}
+
+func TestTryCatchAndPanic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { 1 } catch (e) { 2 }`, "1"},
+		{`try { 1 + true } catch (e) { e }`, "type missmatch: INTEGER + BOOLEAN"},
+		{`try { foo } catch { "caught" }`, "caught"},
+		{`let f = fn() { try { return 1; } catch { 2 }; 3 }; f()`, "1"},
+		{`try { panic("boom") } catch (e) { "caught" }`, "ERROR: panic: boom"},
+		{`try { try { panic("boom") } catch { 1 } } catch { 2 }`, "ERROR: panic: boom"},
+		{`let f = fn() { panic("deep"); 1 }; try { f() } catch { 2 }`, "ERROR: panic: deep"},
+		{`panic("top"); 1`, "ERROR: panic: top"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+
+	evaluated := testEval(`panic("boom")`)
+	if err, ok := evaluated.(*object.Error); !ok || !err.Fatal {
+		t.Errorf("panic did not produce a fatal error, got: %T (%+v)", evaluated, evaluated)
+	}
+}
+
+func TestApplyBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let add = fn(a, b) { a + b }; apply(add, [3, 4])`, "7"},
+		{`apply(fn() { 1 }, [])`, "1"},
+		{`apply(len, ["four"])`, "4"},
+		{`let add = fn(a, b) { a + b }; apply(add, [1])`, "ERROR: wrong number of arguments. got=1, want=2"},
+		{`apply(1, [])`, "ERROR: first argument to `apply` must be FUNCTION, got INTEGER"},
+		{`apply(fn(x) { x }, 1)`, "ERROR: second argument to `apply` must be ARRAY, got INTEGER"},
+		{`let f = fn(a, b) { a }; f(1)`, "ERROR: wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSpreadExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let rest = [2, 3, 4]; [1, ...rest, 5]`, "[1, 2, 3, 4, 5]"},
+		{`[...[], ...[1]]`, "[1]"},
+		{`let add = fn(a, b, c) { a + b + c }; add(...[1, 2, 3])`, "6"},
+		{`let add = fn(a, b, c) { a + b + c }; add(1, ...[2, 3])`, "6"},
+		{`let add = fn(a, b) { a + b }; add(...[1, 2, 3])`, "ERROR: wrong number of arguments. got=3, want=2"},
+		{`[...1]`, "ERROR: cannot spread INTEGER, expected ARRAY"},
+		{`len(..."abc")`, "ERROR: cannot spread STRING, expected ARRAY"},
+		{`...[1]`, "ERROR: spread syntax is only allowed in array literals and call arguments"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSetBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`set(3, 1, 2, 1)`, "set(1, 2, 3)"},
+		{`set()`, "set()"},
+		{`set(...["a", "b", "a"])`, `set("a", "b")`},
+		{`len(set(1, 1, 2))`, "2"},
+		{`add(set(1), 2)`, "set(1, 2)"},
+		{`let s = set(1); add(s, 2); s`, "set(1)"},
+		{`has(set(1, 2), 2)`, "true"},
+		{`has(set(1, 2), 3)`, "false"},
+		{`has(set(1, 2), [1])`, "false"},
+		{`2 in set(1, 2)`, "true"},
+		{`remove(set(1, 2), 1)`, "set(2)"},
+		{`union(set(1, 2), set(2, 3))`, "set(1, 2, 3)"},
+		{`intersect(set(1, 2), set(2, 3))`, "set(2)"},
+		{`difference(set(1, 2), set(2, 3))`, "set(1)"},
+		{`set(1, [2])`, "ERROR: unusable as set element: ARRAY"},
+		{`add(set(), {})`, "ERROR: unusable as set element: HASH"},
+		{`has([1], 1)`, "ERROR: first argument to `has` must be SET, got ARRAY"},
+		{`union(set(), [1])`, "ERROR: arguments to `union` must be SET, got ARRAY"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestHashInsertionOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"c": 3, "a": 1, "b": 2}`, `{"c": 3, "a": 1, "b": 2}`},
+		{`{"c": 3, "a": 1, "c": 4}`, `ERROR: duplicate hash key: "c"`},
+		{`keys({"c": 3, "a": 1, "b": 2})`, `["c", "a", "b"]`},
+		{`values({"c": 3, "a": 1, "b": 2})`, "[3, 1, 2]"},
+		{`entries({"c": 3, true: 1})`, `[["c", 3], [true, 1]]`},
+		{`keys({})`, "[]"},
+		{`fromEntries([["c", 3], [true, 1]])`, `{"c": 3, true: 1}`},
+		{`fromEntries([["a", 1], ["b", 2], ["a", 3]])`, `{"a": 3, "b": 2}`},
+		{`fromEntries([])`, "{}"},
+		{`let h = {"c": 3, "a": 1}; equals(fromEntries(entries(h)), h)`, "true"},
+		{`keys(fromEntries(entries({"c": 3, "a": 1, "b": 2})))`, `["c", "a", "b"]`},
+		{`fromEntries([["a", 1], [2]])`, "ERROR: element 1 of `fromEntries` must be a [key, value] pair, got [2]"},
+		{`fromEntries([1])`, "ERROR: element 0 of `fromEntries` must be a [key, value] pair, got 1"},
+		{`fromEntries([[[1], 2]])`, "ERROR: unusable as hash key: ARRAY"},
+		{`fromEntries({})`, "ERROR: argument to `fromEntries` must be ARRAY, got HASH"},
+		{`groupBy([3, 1, 2, 4], fn(x) { x > 2 })`, "{true: [3, 4], false: [1, 2]}"},
+		{`keys([1])`, "ERROR: argument to `keys` must be HASH, got ARRAY"},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 10; i++ {
+			testInspect(t, testEval(tt.input), tt.expected)
+		}
+	}
+}
+
+func TestNullComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"null == null", true},
+		{"null != null", false},
+		{"null == 0", false},
+		{"0 == null", false},
+		{"null == false", false},
+		{"null != false", true},
+		{`{"a": 1}["b"] == null`, true},
+		{`{"a": 1}["a"] != null`, true},
+		{`[1][5] == null`, true},
+		{"if (false) { 1 } == null", true},
+		{"null", nil},
+		{"null + 1", "type missmatch: NULL + INTEGER"},
+		{"null < null", "unknown operation: NULL < NULL"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			testErrorObject(t, evaluated, expected)
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestBuiltinsBuiltin(t *testing.T) {
+	evaluated := testEval("builtins()")
+	arr, ok := evaluated.(*object.Array)
+	if !ok {
+		t.Fatalf("evaluated is not Array, got: %T (%+v)", evaluated, evaluated)
+	}
+
+	if len(arr.Elements) != len(builtins) {
+		t.Errorf("wrong number of names, expected: %d, got: %d", len(builtins), len(arr.Elements))
+	}
+
+	names := map[string]bool{}
+	previous := ""
+	for _, el := range arr.Elements {
+		name := el.(*object.String).Value
+		if name < previous {
+			t.Errorf("names are not sorted: %s after %s", name, previous)
+		}
+		previous = name
+		names[name] = true
+	}
+
+	for _, name := range []string{"len", "first", "push", "builtins"} {
+		if !names[name] {
+			t.Errorf("builtins() does not contain %s", name)
+		}
+	}
+
+	testInspect(t, testEval("builtins(1)"), "ERROR: wrong number of arguments. got=1, want=0")
+}
+
+func TestHelpBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`help("len")`, "len(x) -> INTEGER: length of a string, array, hash, set or bytes"},
+		{`help("help")`, "help(name) -> STRING: signature and description of a builtin function"},
+		{`help("nope")`, "ERROR: no builtin named `nope`"},
+		{`help(len)`, "ERROR: argument to `help` must be STRING, got BUILTIN"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+
+	for name, builtin := range builtins {
+		if builtin.Signature == "" || builtin.Description == "" {
+			t.Errorf("builtin %s has no documentation", name)
+		}
+	}
+}
+
+func TestFloatExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1.5", "1.5"},
+		{"1.0", "1.0"},
+		{"1.5 + 2", "3.5"},
+		{"2 * 0.25", "0.5"},
+		{"7.5 % 2", "1.5"},
+		{"1 / 2.0", "0.5"},
+		{"-2.5", "-2.5"},
+		{"-0.0", "-0.0"},
+		{"-0.0 == 0.0", "true"},
+		{"1.0 / 0", "Infinity"},
+		{"-1.0 / 0.0", "-Infinity"},
+		{"0.0 / 0.0", "NaN"},
+		{"let nan = 0.0 / 0.0; nan == nan", "false"},
+		{"let nan = 0.0 / 0.0; nan != nan", "true"},
+		{"1.5 > 1", "true"},
+		{"2 == 2.0", "true"},
+		{"isNaN(0.0 / 0.0)", "true"},
+		{"isNaN(1.0)", "false"},
+		{"isNaN(1)", "false"},
+		{"isInf(-1.0 / 0)", "true"},
+		{"isInf(1.0)", "false"},
+		{`isInf("a")`, "ERROR: argument to `isInf` must be INTEGER or FLOAT, got STRING"},
+		{"1.5 + true", "ERROR: type missmatch: FLOAT + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestEqualsBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`equals(1, 1)`, "true"},
+		{`equals(1, 2)`, "false"},
+		{`equals(1, 1.0)`, "false"},
+		{`equals(1, "1")`, "false"},
+		{`equals("a", "a")`, "true"},
+		{`equals(null, null)`, "true"},
+		{`equals([1, [2, 3]], [1, [2, 3]])`, "true"},
+		{`equals([1, [2, 3]], [1, [2, 4]])`, "false"},
+		{`equals([1, 2], [1, 2, 3])`, "false"},
+		{`equals({"a": [1], "b": 2}, {"b": 2, "a": [1]})`, "true"},
+		{`equals({"a": 1}, {"a": 2})`, "false"},
+		{`equals({"a": 1}, {"b": 1})`, "false"},
+		{`let f = fn() { 1 }; equals(f, f)`, "true"},
+		{`equals(fn() { 1 }, fn() { 1 })`, "false"},
+		{`equals(set(1, 2), set(2, 1))`, "true"},
+		{`let a = [1]; a[0] = a; let b = [1]; b[0] = b; equals(a, b)`, "true"},
+		{`let a = [1, 2]; a[0] = a; let b = [1, 3]; b[0] = b; equals(a, b)`, "false"},
+		{`let h = {}; h["self"] = h; let g = {}; g["self"] = g; equals(h, g)`, "true"},
+		{`let a = [1]; a[0] = a; equals(a, [a])`, "true"},
+		{`equals(1)`, "ERROR: wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestMinByMaxBy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`minBy([3, 1, 2], fn(x) { x })`, "1"},
+		{`maxBy([3, 1, 2], fn(x) { x })`, "3"},
+		{`minBy([{"n": "a", "age": 30}, {"n": "b", "age": 20}], fn(p) { p["age"] })["n"]`, "b"},
+		{`maxBy([1.5, 2, -1], fn(x) { x })`, "2"},
+		{`minBy([[1, "a"], [1, "b"]], fn(x) { x[0] })[1]`, "a"},
+		{`maxBy([[1, "a"], [1, "b"]], fn(x) { x[0] })[1]`, "a"},
+		{`minBy([], fn(x) { x })`, "null"},
+		{`maxBy([1, 2], fn(x) { [x] })`, "ERROR: `maxBy` keys are not comparable: ARRAY and ARRAY"},
+		{`minBy([1, 2], fn(x) { if (x == 1) { 1 } else { "a" } })`, "ERROR: `minBy` keys are not comparable: STRING and INTEGER"},
+		{`minBy(1, fn(x) { x })`, "ERROR: argument to `minBy` must be ARRAY, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestTimes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`times(3, fn(i) { i * i })`, "[0, 1, 4]"},
+		{`times(0, fn(i) { i })`, "[]"},
+		{`times(2, fn(i) { "x" })`, `["x", "x"]`},
+		{`times(-1, fn(i) { i })`, "ERROR: first argument to `times` must not be negative, got -1"},
+		{`times("3", fn(i) { i })`, "ERROR: first argument to `times` must be INTEGER, got STRING"},
+		{`times(3, fn(i) { i + "a" })`, "ERROR: type missmatch: INTEGER + STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestPadBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`padLeft("7", 3)`, "  7"},
+		{`padRight("7", 3)`, "7  "},
+		{`padLeft("7", 3, "0")`, "007"},
+		{`padRight("ab", 7, "-=")`, "ab-=-=-"},
+		{`padLeft("ab", 5, "xy")`, "xyxab"},
+		{`padLeft("héé", 4, "·")`, "·héé"},
+		{`padLeft("long", 2)`, "long"},
+		{`padRight("long", -1)`, "long"},
+		{`padLeft("a", 3, "")`, "ERROR: third argument to `padLeft` must not be empty"},
+		{`padRight(1, 3)`, "ERROR: first argument to `padRight` must be STRING, got INTEGER"},
+		{`padLeft("a", "3")`, "ERROR: second argument to `padLeft` must be INTEGER, got STRING"},
+		{`padLeft("a")`, "ERROR: wrong number of arguments. got=1, want=2 or 3"},
+		{`padLeft("a", 9223372036854775807)`, "ERROR: result of `padLeft` too large"},
+		{`padRight("a", 8589934592, "xy")`, "ERROR: result of `padRight` too large"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestLinesWordsBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`lines("a\nb")`, `["a", "b"]`},
+		{`lines("a\r\nb\r\n")`, `["a", "b"]`},
+		{`lines("a\n\nb\n")`, `["a", "", "b"]`},
+		{`len(lines("\n"))`, "1"},
+		{`lines("")`, "[]"},
+		{`words("  hello \t big\n world ")`, `["hello", "big", "world"]`},
+		{`words("   ")`, "[]"},
+		{`words("")`, "[]"},
+		{`lines(1)`, "ERROR: argument to `lines` must be STRING, got INTEGER"},
+		{`words("a", "b")`, "ERROR: wrong number of arguments. got=2, want=1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestGetInBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`getIn({"user": {"address": {"city": "Oslo"}}}, ["user", "address", "city"])`, "Oslo"},
+		{`getIn({"users": [{"name": "a"}, {"name": "b"}]}, ["users", 1, "name"])`, "b"},
+		{`getIn({"user": {}}, ["user", "address", "city"])`, "null"},
+		{`getIn([1, 2], [5])`, "null"},
+		{`getIn([1, 2], [-1])`, "null"},
+		{`getIn({"a": "text"}, ["a", 0])`, "null"},
+		{`getIn({"a": [1]}, ["a", "x"])`, "null"},
+		{`getIn({"a": 1}, [[1]])`, "null"},
+		{`getIn({"a": 1}, [])`, `{"a": 1}`},
+		{`getIn({"a": 1}, "a")`, "ERROR: second argument to `getIn` must be ARRAY, got STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSetInBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`setIn({"a": {"b": 1}}, ["a", "b"], 2)`, `{"a": {"b": 2}}`},
+		{`setIn({"a": 1, "b": 2}, ["a"], 3)`, `{"a": 3, "b": 2}`},
+		{`setIn({}, ["a", "b", "c"], 1)`, `{"a": {"b": {"c": 1}}}`},
+		{`setIn(null, ["a"], 1)`, `{"a": 1}`},
+		{`setIn({"xs": [1, {"n": 2}]}, ["xs", 1, "n"], 3)`, `{"xs": [1, {"n": 3}]}`},
+		{`setIn([1, 2], [0], 9)`, "[9, 2]"},
+		{`setIn({"a": 1}, [], 5)`, "5"},
+		{`let d = {"a": {"b": 1}}; setIn(d, ["a", "b"], 2); d`, `{"a": {"b": 1}}`},
+		{`let d = [[1]]; setIn(d, [0, 0], 2); d`, "[[1]]"},
+		{`setIn({"a": "text"}, ["a", 0], 1)`, "ERROR: cannot set 0 in STRING"},
+		{`setIn([1], [3], 1)`, "ERROR: index out of range: 3"},
+		{`setIn([1], ["a"], 1)`, "ERROR: array index must be INTEGER, got STRING"},
+		{`setIn({}, [[1]], 1)`, "ERROR: unusable as hash key: ARRAY"},
+		{`setIn({}, "a", 1)`, "ERROR: second argument to `setIn` must be ARRAY, got STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`bytes("hi")`, "bytes(104, 105)"},
+		{`bytes("")`, "bytes()"},
+		{`len(bytes("é"))`, "2"},
+		{`bytes("hi")[1]`, "105"},
+		{`bytes("hi")[2]`, "null"},
+		{`string(bytes("héllo"))`, "héllo"},
+		{`bytes("a") == bytes("a")`, "false"},
+		{`equals(bytes("a"), bytes("a"))`, "true"},
+		{`base64Encode(bytes("hello"))`, "aGVsbG8="},
+		{`base64Encode("hello")`, "aGVsbG8="},
+		{`string(base64Decode("aGVsbG8="))`, "hello"},
+		{`base64Decode("!!")`, "ERROR: invalid base64: illegal base64 data at input byte 0"},
+		{`string(base64Decode("/w=="))`, "ERROR: argument to `string` is not valid UTF-8"},
+		{`string("a")`, "ERROR: argument to `string` must be BYTES, got STRING"},
+		{`bytes(1)`, "ERROR: argument to `bytes` must be STRING, got INTEGER"},
+		{`base64Encode(1)`, "ERROR: argument to `base64Encode` must be BYTES or STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDigestBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`md5("")`, "d41d8cd98f00b204e9800998ecf8427e"},
+		{`md5("hello")`, "5d41402abc4b2a76b9719d911017c592"},
+		{`sha256("")`, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{`sha256("hello")`, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{`md5(1)`, "ERROR: argument to `md5` must be STRING, got INTEGER"},
+		{`sha256()`, "ERROR: wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestReadLineBuiltin(t *testing.T) {
+	defer func(in *bufio.Reader) { Input = in }(Input)
+	Input = bufio.NewReader(strings.NewReader("first\r\nsecond\nlast"))
+
+	testInspect(t, testEval(`readLine()`), "first")
+	testInspect(t, testEval(`[readLine(), readLine()]`), `["second", "last"]`)
+	testInspect(t, testEval(`readLine()`), "null")
+	testInspect(t, testEval(`readLine(1)`), "ERROR: wrong number of arguments. got=1, want=0")
+}
+
+func TestPutWritesToOutput(t *testing.T) {
+	defer func(out io.Writer) { Output = out }(Output)
+	var out bytes.Buffer
+	Output = &out
+
+	testInspect(t, testEval(`put(1, "a", [2])`), "null")
+	if out.String() != "1\na\n[2]\n" {
+		t.Errorf("wrong output: %q", out.String())
+	}
+}
+
+func TestExitBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`exit(3); 1`, "exit(3)"},
+		{`exit()`, "exit(0)"},
+		{`let f = fn() { exit(2); 1 }; f() + 1`, "exit(2)"},
+		{`if (true) { exit(4) }; 5`, "exit(4)"},
+		{`try { exit(1) } catch { 2 }`, "exit(1)"},
+		{`times(3, fn(i) { if (i == 1) { exit(i) } else { i } })`, "exit(1)"},
+		{`[1, exit(5), 3]`, "exit(5)"},
+		{`exit("1")`, "ERROR: argument to `exit` must be INTEGER, got STRING"},
+		{`exit(1, 2)`, "ERROR: wrong number of arguments. got=2, want=0 or 1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestTableBuiltin(t *testing.T) {
+	defer func(out io.Writer) { Output = out }(Output)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`table([{"name": "Ada", "age": 36}, {"name": "Grace", "lang": "COBOL"}])`,
+			"+-------+-----+-------+\n" +
+				"| name  | age | lang  |\n" +
+				"+-------+-----+-------+\n" +
+				"| Ada   | 36  |       |\n" +
+				"| Grace |     | COBOL |\n" +
+				"+-------+-----+-------+\n",
+		},
+		{
+			`table([{1: "é"}])`,
+			"+---+\n" +
+				"| 1 |\n" +
+				"+---+\n" +
+				"| é |\n" +
+				"+---+\n",
+		},
+		{`table([])`, ""},
+		{`table([{}])`, ""},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		Output = &out
+
+		testInspect(t, testEval(tt.input), "null")
+		if out.String() != tt.expected {
+			t.Errorf("wrong table for %s.\nexpected:\n%s\ngot:\n%s", tt.input, tt.expected, out.String())
+		}
+	}
+
+	testInspect(t, testEval(`table(1)`), "ERROR: argument to `table` must be ARRAY, got INTEGER")
+	testInspect(t, testEval(`table([{}, 1])`), "ERROR: elements of `table` must be HASH, got INTEGER")
+}
+
+func TestRangeBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`range(4)`, "[0, 1, 2, 3]"},
+		{`range(2, 5)`, "[2, 3, 4]"},
+		{`range(0, 10, 3)`, "[0, 3, 6, 9]"},
+		{`range(10, 0, -1)`, "[10, 9, 8, 7, 6, 5, 4, 3, 2, 1]"},
+		{`range(5, 0, -2)`, "[5, 3, 1]"},
+		{`range(10, 0)`, "[]"},
+		{`range(0, 10, -1)`, "[]"},
+		{`range(0)`, "[]"},
+		{`range(-2)`, "[]"},
+		{`range(0, 10, 0)`, "ERROR: step of `range` must not be zero"},
+		{`range(0, 10, 9223372036854775807)`, "[0]"},
+		{`range(9223372036854775800, 9223372036854775807, 5)`, "[9223372036854775800, 9223372036854775805]"},
+		{`range(9223372036854775807, -9223372036854775807, -9223372036854775807)`, "[9223372036854775807, 0]"},
+		{`let r = range; r(3)`, "[0, 1, 2]"},
+		{`range(-9223372036854775807, 9223372036854775807)`, "ERROR: `range` too large: 18446744073709551614 elements"},
+		{`range("3")`, "ERROR: arguments to `range` must be INTEGER, got STRING"},
+		{`range()`, "ERROR: wrong number of arguments. got=0, want=1 to 3"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestEnumerateBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`enumerate(["a", "b"])`, `[[0, "a"], [1, "b"]]`},
+		{`enumerate(["a", "b"], 1)`, `[[1, "a"], [2, "b"]]`},
+		{`enumerate([])`, "[]"},
+		{`enumerate("ab")`, "ERROR: argument to `enumerate` must be ARRAY, got STRING"},
+		{`enumerate([1], "1")`, "ERROR: second argument to `enumerate` must be INTEGER, got STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestCaseBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`capitalize("hELLO wORLD")`, "Hello world"},
+		{`capitalize("élan")`, "Élan"},
+		{`capitalize("")`, ""},
+		{`title("the quick  brown\tfox")`, "The Quick  Brown\tFox"},
+		{`title("ÉCOLE normale")`, "École Normale"},
+		{`swapCase("Hello World 1")`, "hELLO wORLD 1"},
+		{`swapCase("ÄbC")`, "äBc"},
+		{`capitalize(1)`, "ERROR: argument to `capitalize` must be STRING, got INTEGER"},
+		{`title([])`, "ERROR: argument to `title` must be STRING, got ARRAY"},
+		{`swapCase()`, "ERROR: wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestIgnoreCaseBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`equalsIgnoreCase("Hello", "hELLO")`, "true"},
+		{`equalsIgnoreCase("Straße", "STRASSE")`, "false"},
+		{`equalsIgnoreCase("ÄÖÜ", "äöü")`, "true"},
+		{`equalsIgnoreCase("Kelvin", "Kelvin")`, "true"},
+		{`equalsIgnoreCase("a", "ab")`, "false"},
+		{`containsIgnoreCase("Hello World", "WORLD")`, "true"},
+		{`containsIgnoreCase("ΣΊΣΥΦΟΣ", "σίσυφος")`, "true"},
+		{`containsIgnoreCase("Kelvin", "kel")`, "true"},
+		{`containsIgnoreCase("abc", "")`, "true"},
+		{`containsIgnoreCase("abc", "abcd")`, "false"},
+		{`equalsIgnoreCase(1, "a")`, "ERROR: first argument to `equalsIgnoreCase` must be STRING, got INTEGER"},
+		{`containsIgnoreCase("a", [])`, "ERROR: second argument to `containsIgnoreCase` must be STRING, got ARRAY"},
+		{`equalsIgnoreCase("a")`, "ERROR: wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestCountBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`count("banana", "a")`, "3"},
+		{`count("banana", "ana")`, "1"},
+		{`count("aaaa", "aa")`, "2"},
+		{`count("", "a")`, "0"},
+		{`count([1, 2, 1, "1"], 1)`, "2"},
+		{`count([[1], [2], [1]], [1])`, "2"},
+		{`count([], null)`, "0"},
+		{`count("abc", "")`, "ERROR: second argument to `count` must not be empty"},
+		{`count("abc", 1)`, "ERROR: second argument to `count` must be STRING, got INTEGER"},
+		{`count(1, 1)`, "ERROR: argument to `count` must be STRING or ARRAY, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFrequenciesBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`frequencies(["a", "b", "a"])`, `{"a": 2, "b": 1}`},
+		{`frequencies([3, 1, 3, true, 3])`, "{3: 3, 1: 1, true: 1}"},
+		{`frequencies([])`, "{}"},
+		{`frequencies([[1]])`, "ERROR: unusable as hash key: ARRAY"},
+		{`frequencies("abc")`, "ERROR: argument to `frequencies` must be ARRAY, got STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let [q, r] = divmod(17, 5); [q, r]`, "[3, 2]"},
+		{`divmod(-7, 2)`, "[-3, -1]"},
+		{`let [a] = [[1, 2]]; a`, "[1, 2]"},
+		{`let f = fn() { [1, "x"] }; let [n, s] = f(); s + "y"`, "xy"},
+		{`let swap = fn(p) { let [a, b] = p; [b, a] }; swap([1, 2])`, "[2, 1]"},
+		{`let [a, b] = [1]`, "ERROR: cannot destructure 1 elements into 2 names"},
+		{`let [a] = "a"`, "ERROR: cannot destructure STRING, expected ARRAY"},
+		{`let [a] = [1 + true]`, "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{`divmod(1, 0)`, "ERROR: division by zero: divmod(1, 0)"},
+		{`divmod(1.5, 1)`, "ERROR: first argument to `divmod` must be INTEGER, got FLOAT"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBlockScoping(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let x = 1; if (true) { let x = 2; }; x`, "1"},
+		{`let x = 1; if (true) { let x = 2; x }`, "2"},
+		{`if (true) { let y = 2; }; y`, "ERROR: identifier not found: `y`"},
+		{`let x = 1; if (false) { 0 } else { let x = 3 }; x`, "1"},
+		{`let x = 1; if (false) { 0 } else if (true) { let x = 3 }; x`, "1"},
+		{`let x = 1; try { let x = 2; 1 + true } catch { x }`, "1"},
+		{`let x = 1; try { let x = 2; x } catch { 0 }; x`, "1"},
+		{`let x = 1; if (true) { x = 2; }; x`, "2"},
+		{`let x = 1; if (true) { let x = 5; x = 2; }; x`, "1"},
+		{`let x = 1; if (true) { if (true) { x = x + 1 } }; x`, "2"},
+		{`let x = 1; let f = fn() { x = 10 }; f(); x`, "10"},
+		{`let f = fn() { let x = 1; let g = fn() { x = x + 1; x }; g(); g() }; f()`, "3"},
+		{`let x = 1; let y = 2; x = y = 5; [x, y]`, "[5, 5]"},
+		{`let x = 1; x = 2`, "2"},
+		{`z = 1`, "ERROR: identifier not found: `z`"},
+		{`let x = 1; x = 1 + true; x`, "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{`if (true) { global g = 1 }; g`, "1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestStrBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`str(5)`, "5"},
+		{`str("a")`, "a"},
+		{`str([1, {"a": null}])`, `[1, {"a": null}]`},
+		{`str(["a", "b"])`, `["a", "b"]`},
+		{`str(["a, b"])`, `["a, b"]`},
+		{`let p = {"x": 1, "__str__": fn() { "P(" + str(p["x"]) + ")" }}; str(p)`, "P(1)"},
+		{`let p = {"__str__": fn() { "P" }}; str([p, {"inner": p}])`, `[P, {"inner": P}]`},
+		{`let p = {"__str__": fn() { "<" + str(p) + ">" }}; str(p)`, "<{\"__str__\": fn() {\n((< + str(p)) + >)\n}}>"},
+		{`let p = {"__str__": "not a function"}; str(p)`, `{"__str__": "not a function"}`},
+		{`let p = {"__str__": fn() { 1 }}; str(p)`, "ERROR: __str__ must return STRING, got INTEGER"},
+		{`let p = {"__str__": fn() { 1 + true }}; str(p)`, "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{`str()`, "ERROR: wrong number of arguments. got=0, want=1"},
+		{`let a = [1]; a[0] = a; str(a)`, "[[...]]"},
+		{`let h = {"a": 1}; h["self"] = h; str([h])`, `[{"a": 1, "self": {...}}]`},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestOperatorOverloading(t *testing.T) {
+	vector := `let vec = fn(x, y) {
+		{
+			"x": x,
+			"y": y,
+			"__add__": fn(other) { vec(x + other["x"], y + other["y"]) },
+			"__sub__": fn(other) { vec(x - other["x"], y - other["y"]) },
+			"__mul__": fn(k) { vec(x * k, y * k) },
+			"__eq__": fn(other) { if (x == other["x"]) { y == other["y"] } else { false } },
+			"__lt__": fn(other) { x * x + y * y < other["x"] * other["x"] + other["y"] * other["y"] },
+		}
+	};
+	`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let v = vec(1, 2) + vec(3, 4); [v["x"], v["y"]]`, "[4, 6]"},
+		{`let v = vec(5, 5) - vec(1, 2); [v["x"], v["y"]]`, "[4, 3]"},
+		{`let v = vec(1, 2) * 3; [v["x"], v["y"]]`, "[3, 6]"},
+		{`vec(1, 2) == vec(1, 2)`, "true"},
+		{`vec(1, 2) != vec(1, 2)`, "false"},
+		{`vec(1, 2) != vec(2, 1)`, "true"},
+		{`vec(1, 1) < vec(2, 2)`, "true"},
+		{`vec(1, 1) > vec(2, 2)`, "ERROR: unknown operation: HASH > HASH"},
+		{`vec(1, 1) / 2`, "ERROR: type missmatch: HASH / INTEGER"},
+		{`vec(1, 1) + 1`, "ERROR: index operator not supported: INTEGER"},
+		{`{"__add__": 1} + {}`, "ERROR: unknown operation: HASH + HASH"},
+		{`{"__add__": fn(a, b) { 1 }} + {}`, "ERROR: wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(vector+tt.input), tt.expected)
+	}
+}
+
+func TestStructs(t *testing.T) {
+	point := `struct Point {
+		x, y
+		fn norm() { self.x * self.x + self.y * self.y }
+		fn add(other) { Point(self.x + other.x, self.y + other.y) }
+	}
+	`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`Point`, "struct Point"},
+		{`Point(1, 2)`, "Point(x: 1, y: 2)"},
+		{`Point(y = 2, x = 1)`, "Point(x: 1, y: 2)"},
+		{`Point(1, 2).y`, "2"},
+		{`Point(3, 4).norm()`, "25"},
+		{`let p = Point(1, 2).add(Point(3, 4)); [p.x, p.y]`, "[4, 6]"},
+		{`let norm = Point(1, 1).norm; norm()`, "2"},
+		{`let p = Point(1, 2); p == p`, "true"},
+		{`equals(Point(1, 2), Point(1, 2))`, "true"},
+		{`equals(Point(1, 2), Point(2, 1))`, "false"},
+		{`Point(1)`, "ERROR: wrong number of arguments. got=1, want=2"},
+		{`Point(1, 2).z`, "ERROR: Point has no member `z`"},
+		{`Point(1, 2).norm(1)`, "ERROR: wrong number of arguments. got=1, want=0"},
+		{`[1, 2].x`, "ERROR: member access not supported: ARRAY"},
+		{`Point(1, 2).self`, "ERROR: Point has no member `self`"},
+		{`struct Dup { a, a }`, "ERROR: duplicate member `a` in struct Dup"},
+		{`struct Dup { a fn a() {} }`, "ERROR: duplicate member `a` in struct Dup"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(point+tt.input), tt.expected)
+	}
+}
+
+func TestSelfBinding(t *testing.T) {
+	counter := `let counter = {
+		"count": 2,
+		"double": fn() { self.count * 2 },
+		"plus": fn(n) { self["count"] + n },
+		"__str__": fn() { "counter(" + str(self.count) + ")" },
+		"__add__": fn(other) { self.count + other.count },
+	};
+	`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`counter.count`, "2"},
+		{`counter.missing`, "null"},
+		{`counter.double()`, "4"},
+		{`counter.plus(3)`, "5"},
+		{`let plus = counter.plus; plus(1)`, "3"},
+		{`str(counter)`, `counter(2)`},
+		{`counter + counter`, "4"},
+		{`counter["double"]()`, "ERROR: identifier not found: `self`"},
+		{`let double = counter["double"]; double()`, "ERROR: identifier not found: `self`"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(counter+tt.input), tt.expected)
+	}
+}
+
+func TestTags(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`tagged(tag({"x": 1}, "Point"), "Point")`, "true"},
+		{`tagged(tag({"x": 1}, "Point"), "Vector")`, "false"},
+		{`tagged({"x": 1}, "Point")`, "false"},
+		{`tagged({"x": 1}, "")`, "false"},
+		{`tagged(tag(tag({}, "Point"), ""), "Point")`, "false"},
+		{`tagged(5, "Point")`, "false"},
+		{`struct Point { x } tagged(Point(1), "Point")`, "true"},
+		{`let h = {"x": 1}; tag(h, "Point"); tagged(h, "Point")`, "false"},
+		{`tag({"x": 1, "y": 2}, "Point")`, `{"x": 1, "y": 2}`},
+		{`equals(tag({"x": 1}, "Point"), tag({"x": 1}, "Point"))`, "true"},
+		{`equals(tag({"x": 1}, "Point"), {"x": 1})`, "false"},
+		{`tagged(setIn(tag({"x": 1}, "Point"), ["x"], 2), "Point")`, "true"},
+		{`tag([1], "Point")`, "ERROR: first argument to `tag` must be HASH, got ARRAY"},
+		{`tag({}, 1)`, "ERROR: second argument to `tag` must be STRING, got INTEGER"},
+		{`tagged({}, 1)`, "ERROR: second argument to `tagged` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSmallIntegerCache(t *testing.T) {
+	for _, value := range []int64{minSmallInteger - 1, minSmallInteger, 0, maxSmallInteger, maxSmallInteger + 1} {
+		if got := newInteger(value).Value; got != value {
+			t.Errorf("newInteger(%d) has wrong value, got: %d", value, got)
+		}
+	}
+	if newInteger(7) != newInteger(7) {
+		t.Errorf("small integers are not interned")
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"256 + 1", "257"},
+		{"-128 - 1", "-129"},
+		{"let a = 5; let b = 5; a == b", "true"},
+		{"loop(300, 0, fn(acc, i) { acc + 1 })", "300"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSingletonIdentity(t *testing.T) {
+	if testEval("true") != testEval("true") {
+		t.Errorf("true is not a singleton")
+	}
+
+	tests := []struct {
+		input    string
+		expected object.Object
+	}{
+		{"true", TRUE},
+		{"false", FALSE},
+		{"1 < 2", TRUE},
+		{"!true", FALSE},
+		{"null", NULL},
+		{"if (false) { 1 }", NULL},
+		{`{"a": 1}["b"]`, NULL},
+		{"[1][5]", NULL},
+		{"first([])", NULL},
+		{"equals([1], [1])", TRUE},
+		{"2 in [1]", FALSE},
+		{`getIn({}, ["a"])`, NULL},
+	}
+
+	for _, tt := range tests {
+		if evaluated := testEval(tt.input); evaluated != tt.expected {
+			t.Errorf("%s is not the shared %s, got: %T(%+v)", tt.input, tt.expected.Inspect(), evaluated, evaluated)
+		}
+	}
+}
+
+func TestMapFilterReduce(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map([1, 2, 3], fn(x) { x * 2 })", "[2, 4, 6]"},
+		{"map([5, 6], fn(x, i) { [i, x] })", "[[0, 5], [1, 6]]"},
+		{"map([], fn(x) { x })", "[]"},
+		{`map(["a", "bc"], len)`, "[1, 2]"},
+		{"map([6, 9], gcd)", "[6, 1]"},
+		{"map([1.25, 1.25], round)", "[1.0, 1.0]"},
+		{"filter([1, 2, 3, 4], fn(x) { x % 2 == 0 })", "[2, 4]"},
+		{"filter([5, 6, 7], fn(x, i) { i != 1 })", "[5, 7]"},
+		{"reduce([1, 2, 3], 0, fn(acc, x) { acc + x })", "6"},
+		{"reduce([1, 2, 3], 0, fn(acc, x, i) { acc + x * i })", "8"},
+		{"reduce([], 7, fn(acc, x) { acc + x })", "7"},
+		{"map([1], fn() { 1 })", "ERROR: wrong number of arguments. got=1, want=0"},
+		{"map([1], fn(a, b, c) { 1 })", "ERROR: wrong number of arguments. got=1, want=3"},
+		{"map(1, fn(x) { x })", "ERROR: argument to `map` must be ARRAY, got INTEGER"},
+		{"filter(1, fn(x) { x })", "ERROR: argument to `filter` must be ARRAY, got INTEGER"},
+		{"reduce(1, 0, fn(acc, x) { x })", "ERROR: first argument to `reduce` must be ARRAY, got INTEGER"},
+		{"map([1], fn(x) { x + true })", "ERROR: type missmatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestHashOfBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`hashOf("monkey") == hashOf("mon" + "key")`, "true"},
+		{`hashOf(1) == hashOf(3 - 2)`, "true"},
+		{`hashOf(1) == hashOf(2)`, "false"},
+		{`hashOf(1) == hashOf(true)`, "false"},
+		{`hashOf("1") == hashOf(1)`, "false"},
+		{`hashOf([1])`, "ERROR: unusable as hash key: ARRAY"},
+		{`hashOf(fn(x) { x })`, "ERROR: unusable as hash key: FUNCTION"},
+		{`hashOf()`, "ERROR: wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+
+	// the hash must not change between runs, callers may persist it
+	testIntegerObject(t, testEval(`hashOf("monkey")`), -2591722892340625256)
+	testIntegerObject(t, testEval(`hashOf(1)`), 5229123149639924644)
+}
+
+func TestIndexAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = [1, 2, 3]; a[1] = 5; a", "[1, 5, 3]"},
+		{"let a = [1, 2]; a[0] = 7", "7"},
+		{"let grid = [[0, 0], [0, 0]]; grid[1][0] = 9; grid", "[[0, 0], [9, 0]]"},
+		{`let data = {"a": {"b": 1}}; data["a"]["b"] = 2; data`, `{"a": {"b": 2}}`},
+		{`let h = {"a": 1}; h["b"] = 2; h`, `{"a": 1, "b": 2}`},
+		{`let h = {"a": [1]}; h["a"][0] = h["a"][0] + 1; h["a"]`, "[2]"},
+		{"let a = [1]; let b = a; b[0] = 2; a", "[2]"},
+		{"let a = [0, 0]; let i = 0; a[i] = a[i + 1] = 3; a", "[3, 3]"},
+		{"let a = [1]; a[1] = 2", "ERROR: index out of range: 1"},
+		{"let a = [1]; a[-1] = 2", "ERROR: index out of range: -1"},
+		{`let a = [1]; a["x"] = 2`, "ERROR: array index must be INTEGER, got STRING"},
+		{`let h = {}; h[[1]] = 2`, "ERROR: unusable as hash key: ARRAY"},
+		{`let data = {}; data["a"]["b"] = 1`, "ERROR: index assignment not supported: NULL"},
+		{`let s = "abc"; s[0] = "x"`, "ERROR: index assignment not supported: STRING"},
+		{"missing[0] = 1", "ERROR: identifier not found: `missing`"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestRepeatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let i = 0; repeat { i = i + 1 } until (i == 5); i", "5"},
+		{"let i = 10; repeat { i = i + 1 } until (true); i", "11"},
+		{"repeat { 1 } until (true)", "null"},
+		{"let i = 0; repeat { let next = i + 1; i = next } until (next > 2); i", "3"},
+		{"let i = 0; repeat { i = i + 1; if (i == 4) { break } } until (false); i", "4"},
+		{
+			"let i = 0; let odd = []; repeat { i = i + 1; if (i % 2 == 0) { continue } odd = push(odd, i) } until (i > 6); odd",
+			"[1, 3, 5, 7]",
+		},
+		{"let f = fn() { let i = 0; repeat { i = i + 1; if (i == 3) { return i * 10; } } until (false) }; f()", "30"},
+		{"let i = 0; repeat { i = i + 1; let j = 0; repeat { j = j + 1; break } until (false) } until (i == 2); i", "2"},
+		{"repeat { 1 + true } until (true)", "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{"repeat { 1 } until (x)", "ERROR: identifier not found: `x`"},
+		{`repeat("ab", 2)`, "abab"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestPostfixExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let i = 5; i++; i", "6"},
+		{"let i = 5; i--; i", "4"},
+		{"let i = 5; i++", "5"},
+		{"let i = 5; [i++, i++, i]", "[5, 6, 7]"},
+		{"let i = 0; repeat { i++ } until (i == 3); i", "3"},
+		{"let i = 0; let inc = fn() { i++ }; inc(); inc(); i", "2"},
+		{"let i = 0; if (true) { i++ }; i", "1"},
+		{`let s = "a"; s++`, "ERROR: unknown operation: STRING++"},
+		{"let f = 1.5; f--", "ERROR: unknown operation: FLOAT--"},
+		{"x++", "ERROR: identifier not found: `x`"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDoWhileExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let i = 0; do { i++ } while (i < 5); i", "5"},
+		{"let i = 10; do { i++ } while (i < 5); i", "11"},
+		{"let i = 0; repeat { i++ } until (i < 5); i", "1"},
+		{"do { 1 } while (false)", "null"},
+		{"let i = 0; do { let next = i + 1; i = next } while (next < 3); i", "3"},
+		{"let i = 0; do { i++; if (i == 2) { break } } while (true); i", "2"},
+		{"let i = 0; let n = 0; do { i++; if (i == 2) { continue } n++ } while (i < 4); n", "3"},
+		{"do { 1 } while (x)", "ERROR: identifier not found: `x`"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestForInExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let sum = 0; for (x in [1, 2, 3]) { sum = sum + x }; sum", "6"},
+		{"for (x in [1, 2, 3]) { x }", "null"},
+		{"for (x in []) { x }", "null"},
+		{`let ks = []; for (k in {"c": 1, "a": 2, "b": 3}) { ks = push(ks, k) }; ks`, `["c", "a", "b"]`},
+		{"let seen = []; for (x in [1, 2, 3, 4]) { if (x == 3) { break } seen = push(seen, x) }; seen", "[1, 2]"},
+		{"let seen = []; for (x in [1, 2, 3, 4]) { if (x % 2 == 0) { continue } seen = push(seen, x) }; seen", "[1, 3]"},
+		{"let f = fn(arr) { for (x in arr) { if (x > 1) { return x; } }; -1 }; [f([1, 5, 7]), f([0])]", "[5, -1]"},
+		{"let a = [1, 2]; let n = 0; for (x in a) { a = push(a, x); n++ }; n", "2"},
+		{"let x = 10; for (x in [1, 2]) { x }; x", "10"},
+		{"let pairs = []; for (a in [1, 2]) { for (b in [3, 4]) { if (b == 4) { break } pairs = push(pairs, [a, b]) } }; pairs", "[[1, 3], [2, 3]]"},
+		{"for (x in 5) { x }", "ERROR: cannot iterate over INTEGER"},
+		{`let out = []; for (k, v in {"a": 1, "b": 2}) { out = push(out, k + str(v)) }; out`, `["a1", "b2"]`},
+		{"let out = []; for (i, el in [5, 6, 7]) { out = push(out, i * el) }; out", "[0, 6, 14]"},
+		{"let n = 0; for (i, el in [5, 6, 7]) { if (i == 1) { continue } n = n + el }; n", "12"},
+		{"let k = 1; for (k, v in [9]) { k }; k", "1"},
+		{`for (a, b in "ab") { a }`, "ERROR: cannot iterate over STRING with two variables, expected ARRAY or HASH"},
+		{"for (x in [1]) { x + true }", "ERROR: type missmatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"sign(-5)", "-1"},
+		{"sign(0)", "0"},
+		{"sign(42)", "1"},
+		{"sign(-0.5)", "-1"},
+		{"sign(0.0)", "0"},
+		{"sign(1.0 / 0.0)", "1"},
+		{"sign(0.0 / 0.0)", "ERROR: argument to `sign` must not be NaN"},
+		{`sign("1")`, "ERROR: argument to `sign` must be INTEGER or FLOAT, got STRING"},
+		{"gcd(12, 18)", "6"},
+		{"gcd(-12, 18)", "6"},
+		{"gcd(7, 0)", "7"},
+		{"gcd(0, 0)", "0"},
+		{"gcd(17, 5)", "1"},
+		{"lcm(4, 6)", "12"},
+		{"lcm(-4, 6)", "12"},
+		{"lcm(0, 5)", "0"},
+		{"lcm(9223372036854775807, 2)", "ERROR: integer overflow: lcm(9223372036854775807, 2)"},
+		{"gcd(-9223372036854775807 - 1, 0)", "ERROR: integer overflow: gcd(-9223372036854775808, 0)"},
+		{"gcd(1.5, 2)", "ERROR: first argument to `gcd` must be INTEGER, got FLOAT"},
+		{"lcm(2, 2.5)", "ERROR: second argument to `lcm` must be INTEGER, got FLOAT"},
+		{"gcd(1)", "ERROR: wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestNumberConversions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"toInt(2.7)", "2"},
+		{"toInt(-2.7)", "-2"},
+		{`toInt(2.7, "trunc")`, "2"},
+		{`toInt(2.3, "ceil")`, "3"},
+		{`toInt(-2.3, "ceil")`, "-2"},
+		{`toInt(2.7, "floor")`, "2"},
+		{`toInt(-2.3, "floor")`, "-3"},
+		{`toInt(2.5, "round")`, "3"},
+		{`toInt(-2.5, "round")`, "-3"},
+		{`toInt(2.4, "round")`, "2"},
+		{"toInt(7)", "7"},
+		{`toInt(7, "ceil")`, "7"},
+		{`toInt(2.5, "up")`, `ERROR: unknown rounding mode: "up", want floor, ceil, round or trunc`},
+		{"toInt(2.5, 1)", "ERROR: second argument to `toInt` must be STRING, got INTEGER"},
+		{`toInt("2")`, "ERROR: first argument to `toInt` must be INTEGER or FLOAT, got STRING"},
+		{"toInt(0.0 / 0.0)", "ERROR: cannot convert NaN to INTEGER"},
+		{"toInt(1.0 / 0.0)", "ERROR: cannot convert Infinity to INTEGER"},
+		{"toInt(10000000000.0 * 1000000000.0)", "ERROR: cannot convert 1e+19 to INTEGER"},
+		{"toInt()", "ERROR: wrong number of arguments. got=0, want=1 or 2"},
+		{"toFloat(3)", "3.0"},
+		{"toFloat(-1.5)", "-1.5"},
+		{"toFloat(true)", "ERROR: argument to `toFloat` must be INTEGER or FLOAT, got BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBaseFormatting(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hex(255)", "0xff"},
+		{"hex(-255)", "-0xff"},
+		{"hex(0)", "0x0"},
+		{"oct(8)", "0o10"},
+		{"bin(5)", "0b101"},
+		{"bin(-9223372036854775807 - 1)", "-0b1000000000000000000000000000000000000000000000000000000000000000"},
+		{"toBase(255, 16)", "ff"},
+		{"toBase(-35, 36)", "-z"},
+		{"toBase(10, 2)", "1010"},
+		{"toBase(10, 1)", "ERROR: radix of `toBase` must be between 2 and 36, got 1"},
+		{"toBase(10, 37)", "ERROR: radix of `toBase` must be between 2 and 36, got 37"},
+		{"toBase(1.5, 2)", "ERROR: first argument to `toBase` must be INTEGER, got FLOAT"},
+		{`toBase(1, "2")`, "ERROR: second argument to `toBase` must be INTEGER, got STRING"},
+		{"hex(1.5)", "ERROR: argument to `hex` must be INTEGER, got FLOAT"},
+		{"oct()", "ERROR: wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestInspectCyclicValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = [1, 2]; a[0] = a; a", "[[...], 2]"},
+		{`let h = {"a": 1}; h["me"] = h; h`, `{"a": 1, "me": {...}}`},
+		{`let h = {}; let a = [h]; h["list"] = a; a`, `[{"list": [...]}]`},
+		{"let a = [1]; let b = [a, a]; b", "[[1], [1]]"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFreezeAndSeal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = freeze([1, 2]); a[0] = 5", "ERROR: cannot assign to frozen ARRAY"},
+		{`let h = freeze({"a": 1}); h["a"] = 2`, "ERROR: cannot assign to frozen HASH"},
+		{`let h = freeze({"a": 1}); h["b"] = 2`, "ERROR: cannot assign to frozen HASH"},
+		{`let h = seal({"a": 1}); h["a"] = 2; h`, `{"a": 2}`},
+		{`let h = seal({"a": 1}); h["b"] = 2`, "ERROR: cannot add key b to sealed HASH"},
+		{"let a = [1]; freeze(a); a[0] = 2", "ERROR: cannot assign to frozen ARRAY"},
+		{"let a = freeze([[1]]); a[0][0] = 2; a", "[[2]]"},
+		{"let a = freeze([1]); push(a, 2)", "[1, 2]"},
+		{"let b = push(freeze([1]), 2); b[0] = 3; b", "[3, 2]"},
+		{"[isFrozen(freeze([])), isFrozen(seal({})), isFrozen([]), isFrozen(1)]", "[true, false, false, false]"},
+		{"[isSealed(freeze({})), isSealed(seal({})), isSealed({}), isSealed(1)]", "[true, true, false, false]"},
+		{"[isSealed([]), isSealed(freeze([1]))]", "[false, true]"},
+		{"seal([1, 2])", "ERROR: argument to `seal` must be HASH, got ARRAY"},
+		{"equals(freeze([1]), [1])", "true"},
+		{`freeze("abc")`, "ERROR: argument to `freeze` must be ARRAY or HASH, got STRING"},
+		{"seal(1)", "ERROR: argument to `seal` must be HASH, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestArityBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"arity(fn() { 1 })", "0"},
+		{"arity(fn(a, b) { a })", "2"},
+		{"struct P { x, y fn m(a) { a } } [arity(P), arity(P(1, 2).m)]", "[2, 1]"},
+		{`let h = {"f": fn(a, b, c) { a }}; arity(h.f)`, "3"},
+		{"arity(len)", "1"},
+		{"arity(enumerate)", "1"},
+		{"arity(zip)", "2"},
+		{"arity(builtins)", "0"},
+		{"[arity(padLeft), arity(range), arity(set), arity(eval)]", "[2, 1, 0, 1]"},
+		{"arity(1)", "ERROR: argument to `arity` must be FUNCTION, got INTEGER"},
+		{"arity()", "ERROR: wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDuplicateHashKeys(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"a": 1, "a": 2}`, `ERROR: duplicate hash key: "a"`},
+		{`{true: 1, 1 < 2: 2}`, "ERROR: duplicate hash key: true"},
+		{`{1: "a", 2 - 1: "b"}`, "ERROR: duplicate hash key: 1"},
+		{`let k = "x"; {"x": 1, k: 2}`, `ERROR: duplicate hash key: "x"`},
+		{`{1: "a", "1": "b", true: "c"}`, `{1: "a", "1": "b", true: "c"}`},
+		{`let h = {"a": 1}; h["a"] = 2; h`, `{"a": 2}`},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestTakeWhileDropWhile(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"takeWhile([1, 2, 3, 1], fn(x) { x < 3 })", "[1, 2]"},
+		{"dropWhile([1, 2, 3, 1], fn(x) { x < 3 })", "[3, 1]"},
+		{"takeWhile([5, 1], fn(x) { x < 3 })", "[]"},
+		{"dropWhile([5, 1], fn(x) { x < 3 })", "[5, 1]"},
+		{"takeWhile([1, 2], fn(x) { true })", "[1, 2]"},
+		{"dropWhile([1, 2], fn(x) { true })", "[]"},
+		{"takeWhile([], fn(x) { true })", "[]"},
+		{"let a = [1, 2]; let b = takeWhile(a, fn(x) { true }); b[0] = 9; a", "[1, 2]"},
+		{"takeWhile([1, 2, 3], fn(x) { if (x == 2) { x + true } else { true } })", "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{"dropWhile(1, fn(x) { true })", "ERROR: argument to `dropWhile` must be ARRAY, got INTEGER"},
+		{"takeWhile([1])", "ERROR: wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"scan([1, 2, 3], 0, fn(a, x) { a + x })", "[0, 1, 3, 6]"},
+		{"scan([], 0, fn(a, x) { a + x })", "[0]"},
+		{"scan([2, 3], 1, fn(a, x) { a * x })", "[1, 2, 6]"},
+		{"scan([5, 5], 0, fn(a, x, i) { a + i })", "[0, 0, 1]"},
+		{"scan([1], 0, fn(a, x) { a + true })", "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{"scan(1, 0, fn(a, x) { a })", "ERROR: first argument to `scan` must be ARRAY, got INTEGER"},
+		{"scan([1], 0)", "ERROR: wrong number of arguments. got=2, want=3"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"chunk([1, 2, 3, 4, 5], 2)", "[[1, 2], [3, 4], [5]]"},
+		{"chunk([1, 2, 3, 4], 2)", "[[1, 2], [3, 4]]"},
+		{"chunk([1, 2], 5)", "[[1, 2]]"},
+		{"chunk([], 3)", "[]"},
+		{"let a = [1, 2]; let c = chunk(a, 1); c[0][0] = 9; a", "[1, 2]"},
+		{"chunk([1], 0)", "ERROR: size of `chunk` must be positive, got 0"},
+		{"chunk([1], -2)", "ERROR: size of `chunk` must be positive, got -2"},
+		{"chunk(\"ab\", 1)", "ERROR: first argument to `chunk` must be ARRAY, got STRING"},
+		{"chunk([1], 1.5)", "ERROR: second argument to `chunk` must be INTEGER, got FLOAT"},
+		{"chunk([1])", "ERROR: wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"window([1, 2, 3, 4], 2)", "[[1, 2], [2, 3], [3, 4]]"},
+		{"window([1, 2, 3], 3)", "[[1, 2, 3]]"},
+		{"window([1, 2, 3], 1)", "[[1], [2], [3]]"},
+		{"window([1, 2], 3)", "[]"},
+		{"window([], 1)", "[]"},
+		{"map(window([1, 2, 3, 4], 2), fn(w) { (w[0] + w[1]) / 2 })", "[1, 2, 3]"},
+		{"window([1], 0)", "ERROR: size of `window` must be positive, got 0"},
+		{"window(1, 2)", "ERROR: first argument to `window` must be ARRAY, got INTEGER"},
+		{"window([1], \"2\")", "ERROR: second argument to `window` must be INTEGER, got STRING"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestCharsBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`toChars("abc")`, `["a", "b", "c"]`},
+		{`toChars("")`, "[]"},
+		{`len(toChars("héllo, 世界"))`, "9"},
+		{`toChars("世界")[1]`, "界"},
+		{`fromChars(["a", "b", "c"])`, "abc"},
+		{`fromChars([])`, ""},
+		{`equals(fromChars(toChars("héllo, 世界")), "héllo, 世界")`, "true"},
+		{`fromChars(["a", "bc"])`, "ERROR: element 1 of `fromChars` must be a one-character STRING, got bc"},
+		{`fromChars(["", "a"])`, "ERROR: element 0 of `fromChars` must be a one-character STRING, got "},
+		{`fromChars([1])`, "ERROR: element 0 of `fromChars` must be a one-character STRING, got 1"},
+		{`fromChars("abc")`, "ERROR: argument to `fromChars` must be ARRAY, got STRING"},
+		{`toChars(1)`, "ERROR: argument to `toChars` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFloatPrecision(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0.1 + 0.2", "0.3"},
+		{"1.0 / 3.0", "0.333333333333333"},
+		{"2.0 * 1.5", "3.0"},
+		{"1234567.0", "1234567.0"},
+		{"equals(0.1 + 0.2, 0.3)", "false"},
+		{"(0.1 + 0.2) * 10000000000000000.0", "3e+15"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+
+	defer func(precision int) { object.FloatPrecision = precision }(object.FloatPrecision)
+	object.FloatPrecision = 0
+	testInspect(t, testEval("0.1 + 0.2"), "0.30000000000000004")
+	object.FloatPrecision = 3
+	testInspect(t, testEval("3.14159"), "3.14")
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"round(2.5)", "3.0"},
+		{"round(-2.5)", "-3.0"},
+		{"round(2.4)", "2.0"},
+		{"round(3.14159, 2)", "3.14"},
+		{"round(2.675, 1)", "2.7"},
+		{"round(1234.5, -2)", "1200.0"},
+		{"round(0.1 + 0.2, 20)", "0.3"},
+		{"round(1.5, 400)", "1.5"},
+		{"round(1.5, -400)", "0.0"},
+		{"round(7)", "7"},
+		{"round(7, -1)", "7"},
+		{"round(\"1.5\")", "ERROR: first argument to `round` must be INTEGER or FLOAT, got STRING"},
+		{"round(1.5, 1.0)", "ERROR: second argument to `round` must be INTEGER, got FLOAT"},
+		{"round()", "ERROR: wrong number of arguments. got=0, want=1 or 2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestParallelAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a, b = 1, 2; [a, b]", "[1, 2]"},
+		{"let a, b = 1, 2; a, b = b, a; [a, b]", "[2, 1]"},
+		{"let a, b, c = 1, 2, 3; a, b, c = c, a, b; [a, b, c]", "[3, 1, 2]"},
+		{"let x = 1; let f = fn() { let y = 2; x, y = y, x; y }; [f(), x]", "[1, 2]"},
+		{"let a, b = 0, 1; for (i in range(5)) { a, b = b, a + b; }; a", "5"},
+		{"let a, b = 1, 1 + true", "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{"let a = 1; a, b = 2, 3", "ERROR: identifier not found: `b`"},
+		{"let a = 1; try { a, b = 2, 3; } catch { a }", "1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDefer(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let log = ""; let f = fn() { defer log = log + "a"; defer log = log + "b"; log = log + "c"; }; f(); log`, "cba"},
+		{`let log = ""; let f = fn(x) { defer log = log + "d"; if (x) { return 1; }; 2 }; [f(true), f(false), log]`, `[1, 2, "dd"]`},
+		{`let f = fn() { let x = 1; defer x = 5; return x; }; f()`, "1"},
+		{`let log = ""; let f = fn() { for (i in [1, 2, 3]) { defer log = log + str(i); } }; f(); log`, "321"},
+		{`let log = ""; let g = fn() { defer log = log + "g"; }; let f = fn() { defer log = log + "f"; g(); log = log + "x"; }; f(); log`, "gxf"},
+		{`let log = ""; let f = fn() { defer log = log + "d"; 1 + true }; try { f() } catch { log }`, "d"},
+		{`let f = fn() { defer 1 + true; 2 }; f()`, "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{`let f = fn() { defer 1 + true; foo }; f()`, "ERROR: identifier not found: `foo`"},
+		{`let log = ""; map([1, 2], fn(x) { defer log = log + str(x); x }); log`, "12"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBench(t *testing.T) {
+	evaluated := testEval(`let calls = 0; let ms = bench(fn() { calls = calls + 1; [1, 2, 3] }, 5); [calls, ms < 0.0]`)
+	testInspect(t, evaluated, "[5, false]")
+
+	if _, ok := testEval(`bench(fn() { 1 }, 1)`).(*object.Float); !ok {
+		t.Errorf("bench did not return a FLOAT")
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`bench(fn() { 1 }, 0)`, "ERROR: second argument to `bench` must be positive, got 0"},
+		{`bench(fn() { 1 }, -3)`, "ERROR: second argument to `bench` must be positive, got -3"},
+		{`bench(fn() { 1 }, 1.5)`, "ERROR: second argument to `bench` must be INTEGER, got FLOAT"},
+		{`bench(fn() { 1 + true }, 3)`, "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{`bench(fn(x) { x }, 3)`, "ERROR: wrong number of arguments. got=0, want=1"},
+		{`bench(1, 3)`, "ERROR: not a function: INTEGER"},
+		{`bench(fn() { 1 })`, "ERROR: wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestStructuredErrors(t *testing.T) {
+	input := `let f = fn(x) {
+  x + true
+};
+let g = fn() { f(1) };
+try { g() } catch (e, err) { [errorMessage(err), errorLine(err), errorColumn(err), errorTrace(err)] }`
+
+	testInspect(t, testEval(input), `["type missmatch: INTEGER + BOOLEAN", 2, 5, ["f (line 4, column 17)", "g (line 5, column 8)"]]`)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { foo } catch (e, err) { [errorLine(err), errorColumn(err), errorTrace(err)] }", "[1, 7, []]"},
+		{"try { len(1) } catch (e, err) { errorTrace(err) }", `["len (line 1, column 10)"]`},
+		{`let h = {"f": fn() { 1 + true }}; try { h.f() } catch (e, err) { errorTrace(err) }`, `["h.f (line 1, column 44)"]`},
+		{"try { fn() { foo }() } catch (e, err) { errorTrace(err) }", `["fn (line 1, column 19)"]`},
+		{"try { foo } catch (e, err) { err }", "identifier not found: `foo`"},
+		{"try { foo } catch (e, err) { str(err) }", "identifier not found: `foo`"},
+		{"let err = try { foo } catch (e, err) { err }; errorMessage(err)", "identifier not found: `foo`"},
+		{"try { foo } catch (e, err) { [e, err] }", `["identifier not found: ` + "`foo`" + `", identifier not found: ` + "`foo`" + `]`},
+		{`try { foo } catch (e) { "failed: " + e }`, "failed: identifier not found: `foo`"},
+		{`try { foo } catch (e) { [len(e), equals(e, "identifier not found: ` + "`foo`" + `")] }`, "[27, true]"},
+		{"try { foo } catch (e) { errorMessage(e) }", "ERROR: argument to `errorMessage` must be CAUGHT_ERROR, got STRING"},
+		{"errorLine(1)", "ERROR: argument to `errorLine` must be CAUGHT_ERROR, got INTEGER"},
+		{`errorMessage("boom")`, "ERROR: argument to `errorMessage` must be CAUGHT_ERROR, got STRING"},
+		{"errorTrace()", "ERROR: wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+
+	err, ok := testEval("let a = 1;\nlet b = a[0];").(*object.Error)
+	if !ok || err.Line != 2 || err.Column != 10 {
+		t.Errorf("error not located at 2:10, got: %#v", err)
+	}
+}
+
+func TestEvalAndParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`eval("1 + 2")`, "3"},
+		{`let x = 5; eval("x * 2")`, "10"},
+		{`eval("let y = 7;"); y`, "7"},
+		{`let f = fn(a) { eval("a + 1") }; f(41)`, "42"},
+		{`map(["1", "2 * 3"], eval)`, "[1, 6]"},
+		{`eval("")`, "null"},
+		{`eval("return 1; 2")`, "1"},
+		{`eval("return 5")`, "5"},
+		{`let f = fn() { eval("return 5") + 1 }; f()`, "6"},
+		{`eval("let m = macro(a) { quote(unquote(a) * 2) }; m(21)")`, "42"},
+		{`eval("let m = macro(a) { 1 }; m(2)")`, "ERROR: macro `m` must return QUOTE, got INTEGER"},
+		{`eval("1 + true")`, "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{`try { eval("foo") } catch (e) { e }`, "identifier not found: `foo`"},
+		{`eval("let = 1")`, "ERROR: cannot parse: Expect token to be ident, got = instead; no prefix parse function for = found"},
+		{`let s = "eval(s)"; eval(s)`, "ERROR: `eval` nested too deeply"},
+		{`eval(1)`, "ERROR: argument to `eval` must be STRING, got INTEGER"},
+		{`parse("1 + 2 * 3")`, "(1 + (2 * 3))"},
+		{`parse("let x = -a[1]")`, "let x = (-(a[1]));"},
+		{`parse("let")`, "ERROR: cannot parse: Expect token to be ident, got EOF instead"},
+		{`eval(parse("2 * (3 + 4)"))`, "14"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestQuoteUnquote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(5)`, "QUOTE(5)"},
+		{`quote(5 + 8)`, "QUOTE((5 + 8))"},
+		{`quote(foobar + barfoo)`, "QUOTE((foobar + barfoo))"},
+		{`quote(unquote(4 + 4))`, "QUOTE(8)"},
+		{`let foobar = 8; quote(unquote(foobar) + 1)`, "QUOTE((8 + 1))"},
+		{`quote(unquote(true == false))`, "QUOTE(false)"},
+		{`quote(unquote(1.5) * unquote(2.0))`, "QUOTE((1.5 * 2.0))"},
+		{`quote(unquote(null))`, "QUOTE(null)"},
+		{`quote(unquote(quote(4 + 4)))`, "QUOTE((4 + 4))"},
+		{`let q = quote(4 + 4); quote(unquote(4 + 4) + unquote(q))`, "QUOTE((8 + (4 + 4)))"},
+		{`quote(f(unquote(1 + 1), [unquote(2 * 2)]))`, "QUOTE(f(2, [4]))"},
+		{`quote(unquote([1]))`, "ERROR: cannot unquote ARRAY"},
+		{`quote(unquote(foo))`, "ERROR: identifier not found: `foo`"},
+		{`quote(unquote(1, 2))`, "ERROR: wrong number of arguments to `unquote`. got=2, want=1"},
+		{`quote(1, 2)`, "ERROR: wrong number of arguments to `quote`. got=2, want=1"},
+		{`let f = fn() { let m = macro(x) { x }; 1 }; f()`, "ERROR: macros can only be defined by top-level let statements"},
+		{`let f = fn(x) { quote(unquote(x) + 1) }; [f(1), f(2)]`, "[QUOTE((1 + 1)), QUOTE((2 + 1))]"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDefineMacros(t *testing.T) {
+	input := `let number = 1;
+let function = fn(x, y) { x + y };
+let mymacro = macro(x, y) { x + y; };`
+
+	program := parser.New(lexer.New(input)).ParseProgram()
+	env := object.NewEnvironment()
+	DefineMacros(program, env)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("wrong number of statements, got: %d", len(program.Statements))
+	}
+	for _, name := range []string{"number", "function"} {
+		if _, ok := env.Get(name); ok {
+			t.Errorf("%s should not be defined", name)
+		}
+	}
+
+	obj, ok := env.Get("mymacro")
+	if !ok {
+		t.Fatalf("macro not in environment")
+	}
+	macro, ok := obj.(*object.Macro)
+	if !ok {
+		t.Fatalf("object is not Macro, got: %T (%+v)", obj, obj)
+	}
+	if len(macro.Parameters) != 2 || macro.Parameters[0].String() != "x" || macro.Parameters[1].String() != "y" {
+		t.Errorf("wrong macro parameters, got: %v", macro.Parameters)
+	}
+	if macro.Body.String() != "(x + y)" {
+		t.Errorf("wrong macro body, got: %q", macro.Body.String())
+	}
+}
+
+func TestExpandMacros(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`let infixExpression = macro() { quote(1 + 2); }; infixExpression();`,
+			`(1 + 2)`,
+		},
+		{
+			`let reverse = macro(a, b) { quote(unquote(b) - unquote(a)); }; reverse(2 + 2, 10 - 5);`,
+			`(10 - 5) - (2 + 2)`,
+		},
+		{
+			`let unless = macro(condition, consequence, alternative) {
+				quote(if (!(unquote(condition))) { unquote(consequence); } else { unquote(alternative); });
+			};
+			unless(10 > 5, puts("not greater"), puts("greater"));`,
+			`if (!(10 > 5)) { puts("not greater") } else { puts("greater") }`,
+		},
+		{
+			`let twice = macro(x) { quote(unquote(x) * 2) }; let f = fn(y) { twice(y + 1) };`,
+			`let f = fn(y) { (y + 1) * 2 };`,
+		},
+		{
+			`let m = macro(a) { quote(unquote(a) + 1) }; m(2); m(5);`,
+			`(2 + 1); (5 + 1);`,
+		},
+		{
+			`let unless = macro(c, a, b) { quote(if (!(unquote(c))) { unquote(a) } else { unquote(b) }) };
+			unless(1 > 2, "no", "yes"); unless(3 > 2, "no", "yes");`,
+			`if (!(1 > 2)) { "no" } else { "yes" }; if (!(3 > 2)) { "no" } else { "yes" };`,
+		},
+	}
+
+	for _, tt := range tests {
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+		expected := parser.New(lexer.New(tt.expected)).ParseProgram()
+
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		expanded, err := ExpandMacros(program, env)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Message)
+		}
+
+		if expanded.String() != expected.String() {
+			t.Errorf("not equal. want=%q, got=%q", expected.String(), expanded.String())
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`let m = macro(x) { quote(x) }; m(1, 2)`, "wrong number of arguments to macro `m`. got=2, want=1"},
+		{`let m = macro(x) { 1 }; m(1)`, "macro `m` must return QUOTE, got INTEGER"},
+		{`let m = macro(x) { }; m(1)`, "macro `m` must return QUOTE, got NULL"},
+		{`let m = macro(x) { 1 + true }; m(1)`, "type missmatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range errorTests {
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		_, err := ExpandMacros(program, env)
+		if err == nil || err.Message != tt.expected {
+			t.Errorf("wrong error for %q, expected: %q, got: %v", tt.input, tt.expected, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package evaluator
+
+import (
+	"strings"
+
+	"monkey/src/object"
+)
+
+// evalFormatExpression renders `format % args` printf-style. args is usually
+// an array, any other value is taken as the single argument.
+//
+// Supported verbs are %d (INTEGER), %s and %v (any value, as Inspect) and %%.
+func evalFormatExpression(format, args object.Object) object.Object {
+	var values []object.Object
+	if arr, ok := args.(*object.Array); ok {
+		values = arr.Elements
+	} else {
+		values = []object.Object{args}
+	}
+
+	var out strings.Builder
+	runes := []rune(format.(*object.String).Value)
+	next := 0
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' {
+			out.WriteRune(runes[i])
+			continue
+		}
+
+		i++
+		if i == len(runes) {
+			return newError("format string ends with a lone %%")
+		}
+
+		verb := runes[i]
+		if verb == '%' {
+			out.WriteRune('%')
+			continue
+		}
+
+		if next >= len(values) {
+			return newError("not enough arguments for format string: got %d", len(values))
+		}
+		value := values[next]
+		next++
+
+		switch verb {
+		case 'd':
+			if value.Type() != object.INTEGER_OBJ {
+				return newError("%%d expects INTEGER, got %s", value.Type())
+			}
+			out.WriteString(value.Inspect())
+		case 's', 'v':
+			out.WriteString(value.Inspect())
+		default:
+			return newError("unknown format verb: %%%c", verb)
+		}
+	}
+
+	if next != len(values) {
+		return newError("too many arguments for format string: want %d, got %d", next, len(values))
+	}
+
+	return &object.String{Value: out.String()}
+}
@@ -0,0 +1,35 @@
+package evaluator
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"monkey/src/object"
+)
+
+// Input and Output are the streams readLine reads from and put writes to.
+// They default to the standard streams of the process; embedders and tests
+// can point them elsewhere. Input is buffered, so whoever else reads from the
+// same stream should read through it too.
+var (
+	Input            = bufio.NewReader(os.Stdin)
+	Output io.Writer = os.Stdout
+)
+
+func builtinReadLine(args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0", len(args))
+	}
+
+	line, err := Input.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return NULL
+	}
+	if err != nil && err != io.EOF {
+		return newError("readLine: %s", err)
+	}
+
+	return &object.String{Value: strings.TrimRight(line, "\r\n")}
+}
@@ -0,0 +1,100 @@
+package evaluator
+
+import (
+	"monkey/src/ast"
+	"monkey/src/object"
+)
+
+var (
+	BREAK    = &object.Break{}
+	CONTINUE = &object.Continue{}
+)
+
+// evalPostConditionLoop runs body until isTruthy of the condition equals
+// until: `repeat ... until` stops once the condition holds, `do ... while`
+// as soon as it does not. The condition is evaluated in the scope of the
+// iteration that just ran, so it can refer to the bindings made by the body.
+func evalPostConditionLoop(body *ast.BlockStatement, cond ast.Expression, until bool, env *object.Environment) object.Object {
+	for {
+		if err := checkRuntime(env); err != nil {
+			return err
+		}
+
+		iterationEnv := object.NewEnclosedEnvironment(env)
+		if result, stop := evalLoopBody(body, iterationEnv); stop {
+			return result
+		}
+
+		condition := Eval(cond, iterationEnv)
+		if isError(condition) {
+			return condition
+		}
+		if isTruthy(condition) == until {
+			return NULL
+		}
+	}
+}
+
+// evalForInExpression binds the variable to every element of an array, or
+// every key of a hash in insertion order, in a fresh scope per iteration.
+// With two variables the first one gets the index or key and the second one
+// the element or value. The pairs are collected up front, so changing the
+// iterable from the body does not affect the iterations.
+func evalForInExpression(node *ast.ForInExpression, env *object.Environment) object.Object {
+	iterable := Eval(node.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	var pairs []object.HashPair
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for i, el := range iterable.Elements {
+			pairs = append(pairs, object.HashPair{Key: newInteger(int64(i)), Value: el})
+		}
+	case *object.Hash:
+		for _, pair := range iterable.OrderedPairs() {
+			value := pair.Value
+			if node.Key == nil {
+				value = pair.Key
+			}
+			pairs = append(pairs, object.HashPair{Key: pair.Key, Value: value})
+		}
+	default:
+		if node.Key != nil {
+			return newError("cannot iterate over %s with two variables, expected ARRAY or HASH", iterable.Type())
+		}
+		return newError("cannot iterate over %s", iterable.Type())
+	}
+
+	for _, pair := range pairs {
+		if err := checkRuntime(env); err != nil {
+			return err
+		}
+
+		iterationEnv := object.NewEnclosedEnvironment(env)
+		if node.Key != nil {
+			iterationEnv.Set(node.Key.Value, pair.Key)
+		}
+		iterationEnv.Set(node.Variable.Value, pair.Value)
+		if result, stop := evalLoopBody(node.Body, iterationEnv); stop {
+			return result
+		}
+	}
+
+	return NULL
+}
+
+// evalLoopBody runs one iteration of a loop. It reports whether the loop has
+// to stop, together with what the loop evaluates to then: null after a break,
+// or the return value, error or exit that ended the body.
+func evalLoopBody(body *ast.BlockStatement, env *object.Environment) (object.Object, bool) {
+	switch result := evalBlockStatement(body, env).(type) {
+	case *object.Break:
+		return NULL, true
+	case *object.ReturnValue, *object.Error, *object.Exit:
+		return result, true
+	default:
+		return nil, false
+	}
+}
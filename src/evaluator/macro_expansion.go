@@ -0,0 +1,82 @@
+package evaluator
+
+import (
+	"monkey/src/ast"
+	"monkey/src/object"
+)
+
+// DefineMacros binds the macros of the top-level `let name = macro(...)`
+// statements of program in env and removes those statements, so they are
+// gone by the time the program is evaluated.
+func DefineMacros(program *ast.Program, env *object.Environment) {
+	statements := []ast.Statement{}
+
+	for _, stm := range program.Statements {
+		if let, ok := stm.(*ast.LetStatement); ok {
+			if macro, ok := let.Value.(*ast.MacroLiteral); ok {
+				env.Set(let.Name.Value, &object.Macro{
+					Parameters: macro.Parameters,
+					Body:       macro.Body,
+					Env:        env,
+				})
+				continue
+			}
+		}
+		statements = append(statements, stm)
+	}
+
+	program.Statements = statements
+}
+
+// ExpandMacros replaces the calls of the macros bound in env within program
+// by the code the macros return for them. A macro gets its arguments as
+// quotes of the unevaluated code and must return a quote itself. When a
+// macro fails, the error is returned and the rest of the program is left
+// unexpanded.
+func ExpandMacros(program ast.Node, env *object.Environment) (ast.Node, *object.Error) {
+	var failed *object.Error
+
+	expanded := ast.Modify(program, func(node ast.Node) ast.Node {
+		if failed != nil {
+			return node
+		}
+		call, ok := node.(*ast.CallExpression)
+		if !ok {
+			return node
+		}
+		ident, ok := call.Function.(*ast.Identifier)
+		if !ok {
+			return node
+		}
+		obj, _ := env.Get(ident.Value)
+		macro, ok := obj.(*object.Macro)
+		if !ok {
+			return node
+		}
+
+		if len(call.Arguments) != len(macro.Parameters) {
+			failed = newError("wrong number of arguments to macro `%s`. got=%d, want=%d",
+				ident.Value, len(call.Arguments), len(macro.Parameters))
+			return node
+		}
+
+		macroEnv := object.NewEnclosedEnvironment(macro.Env)
+		for i, param := range macro.Parameters {
+			macroEnv.Set(param.Value, &object.Quote{Node: call.Arguments[i]})
+		}
+
+		switch result := evalFunctionBody(macro.Body, macroEnv).(type) {
+		case *object.Quote:
+			return result.Node
+		case *object.Error:
+			failed = result
+		case nil:
+			failed = newError("macro `%s` must return QUOTE, got %s", ident.Value, object.NULL_OBJ)
+		default:
+			failed = newError("macro `%s` must return QUOTE, got %s", ident.Value, result.Type())
+		}
+		return node
+	})
+
+	return expanded, failed
+}
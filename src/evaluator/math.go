@@ -0,0 +1,297 @@
+package evaluator
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+
+	"monkey/src/object"
+)
+
+var mathBuiltins = map[string]*object.Builtin{
+	"sign": {
+		Signature:   "sign(x) -> INTEGER",
+		Description: "-1, 0 or 1 depending on the sign of a number",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+
+			switch arg := args[0].(type) {
+			case *object.Integer:
+				return newInteger(int64(sign(float64(arg.Value))))
+			case *object.Float:
+				if math.IsNaN(arg.Value) {
+					return newError("argument to `sign` must not be NaN")
+				}
+				return newInteger(int64(sign(arg.Value)))
+			default:
+				return newError("argument to `sign` must be INTEGER or FLOAT, got %s", args[0].Type())
+			}
+		},
+	},
+	"gcd": {
+		Signature:   "gcd(a, b) -> INTEGER",
+		Description: "greatest common divisor of the absolute values of two integers",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			a, b, err := integerPair("gcd", args)
+			if err != nil {
+				return err
+			}
+			return checkedInteger("gcd", args, gcd(a, b))
+		},
+	},
+	"lcm": {
+		Signature:   "lcm(a, b) -> INTEGER",
+		Description: "least common multiple of the absolute values of two integers",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			a, b, err := integerPair("lcm", args)
+			if err != nil {
+				return err
+			}
+			if a == 0 || b == 0 {
+				return newInteger(0)
+			}
+
+			hi, lo := bits.Mul64(a/gcd(a, b), b)
+			if hi != 0 {
+				return newError("integer overflow: lcm(%s, %s)", args[0].Inspect(), args[1].Inspect())
+			}
+			return checkedInteger("lcm", args, lo)
+		},
+	},
+	"toInt": {
+		Signature:   "toInt(x, mode = \"trunc\") -> INTEGER",
+		Description: "number converted to an integer, rounding floats by mode: floor, ceil, round (halves away from zero) or trunc",
+		Arity:       1,
+		Variadic:    true,
+		Fn:          builtinToInt,
+	},
+	"round": {
+		Signature:   "round(x, digits = 0) -> NUMBER",
+		Description: "float rounded to digits decimal places, halves away from zero; integers are returned unchanged",
+		Arity:       1,
+		Variadic:    true,
+		Fn:          builtinRound,
+	},
+	"toFloat": {
+		Signature:   "toFloat(x) -> FLOAT",
+		Description: "number converted to a float",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+
+			switch arg := args[0].(type) {
+			case *object.Integer:
+				return &object.Float{Value: float64(arg.Value)}
+			case *object.Float:
+				return arg
+			default:
+				return newError("argument to `toFloat` must be INTEGER or FLOAT, got %s", args[0].Type())
+			}
+		},
+	},
+	"hex": {
+		Signature:   "hex(n) -> STRING",
+		Description: "integer in base 16 prefixed with 0x",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return prefixedBase("hex", args, "0x", 16)
+		},
+	},
+	"oct": {
+		Signature:   "oct(n) -> STRING",
+		Description: "integer in base 8 prefixed with 0o",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return prefixedBase("oct", args, "0o", 8)
+		},
+	},
+	"bin": {
+		Signature:   "bin(n) -> STRING",
+		Description: "integer in base 2 prefixed with 0b",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return prefixedBase("bin", args, "0b", 2)
+		},
+	},
+	"toBase": {
+		Signature:   "toBase(n, radix) -> STRING",
+		Description: "integer in a base from 2 to 36, using lower case letters for digits above 9",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError("wrong number of arguments. got=%d, want=2", len(args))
+			}
+			n, ok := args[0].(*object.Integer)
+			if !ok {
+				return newError("first argument to `toBase` must be INTEGER, got %s", args[0].Type())
+			}
+			radix, ok := args[1].(*object.Integer)
+			if !ok {
+				return newError("second argument to `toBase` must be INTEGER, got %s", args[1].Type())
+			}
+			if radix.Value < 2 || radix.Value > 36 {
+				return newError("radix of `toBase` must be between 2 and 36, got %d", radix.Value)
+			}
+			return &object.String{Value: strconv.FormatInt(n.Value, int(radix.Value))}
+		},
+	},
+}
+
+// prefixedBase backs hex, oct and bin. The sign of negative numbers goes
+// before the prefix, like -0xff.
+func prefixedBase(name string, args []object.Object, prefix string, base int) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	n, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("argument to `%s` must be INTEGER, got %s", name, args[0].Type())
+	}
+
+	sign := ""
+	if n.Value < 0 {
+		sign = "-"
+	}
+	return &object.String{Value: sign + prefix + strconv.FormatUint(absUint(n.Value), base)}
+}
+
+// roundingModes are the modes `toInt` accepts.
+var roundingModes = map[string]func(float64) float64{
+	"floor": math.Floor,
+	"ceil":  math.Ceil,
+	"round": math.Round,
+	"trunc": math.Trunc,
+}
+
+func builtinToInt(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2", len(args))
+	}
+
+	mode := "trunc"
+	if len(args) == 2 {
+		m, ok := args[1].(*object.String)
+		if !ok {
+			return newError("second argument to `toInt` must be STRING, got %s", args[1].Type())
+		}
+		mode = m.Value
+	}
+	round, ok := roundingModes[mode]
+	if !ok {
+		return newError("unknown rounding mode: %q, want floor, ceil, round or trunc", mode)
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Integer:
+		return arg
+	case *object.Float:
+		rounded := round(arg.Value)
+		// -2^63 is exact as a float, 2^63 is the first value past the range
+		if math.IsNaN(rounded) || rounded < math.MinInt64 || rounded >= math.MaxInt64 {
+			return newError("cannot convert %s to INTEGER", arg.Inspect())
+		}
+		return newInteger(int64(rounded))
+	default:
+		return newError("first argument to `toInt` must be INTEGER or FLOAT, got %s", args[0].Type())
+	}
+}
+
+func builtinRound(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2", len(args))
+	}
+
+	digits := int64(0)
+	if len(args) == 2 {
+		d, ok := args[1].(*object.Integer)
+		if !ok {
+			return newError("second argument to `round` must be INTEGER, got %s", args[1].Type())
+		}
+		digits = d.Value
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Integer:
+		return arg
+	case *object.Float:
+		// past the range of float64 the scale over- or underflows, and there
+		// is nothing left to round either way
+		if digits > 308 || math.IsInf(arg.Value, 0) || math.IsNaN(arg.Value) {
+			return arg
+		}
+		if digits < -308 {
+			return &object.Float{Value: math.Copysign(0, arg.Value)}
+		}
+		scale := math.Pow10(int(digits))
+		scaled := arg.Value * scale
+		if math.IsInf(scaled, 0) {
+			return arg
+		}
+		return &object.Float{Value: math.Round(scaled) / scale}
+	default:
+		return newError("first argument to `round` must be INTEGER or FLOAT, got %s", args[0].Type())
+	}
+}
+
+func init() {
+	for name, builtin := range mathBuiltins {
+		builtins[name] = builtin
+	}
+}
+
+func sign(x float64) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// integerPair returns the absolute values of two integer arguments. They are
+// unsigned so the absolute value of the smallest integer fits.
+func integerPair(name string, args []object.Object) (uint64, uint64, *object.Error) {
+	if len(args) != 2 {
+		return 0, 0, newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	a, ok := args[0].(*object.Integer)
+	if !ok {
+		return 0, 0, newError("first argument to `%s` must be INTEGER, got %s", name, args[0].Type())
+	}
+	b, ok := args[1].(*object.Integer)
+	if !ok {
+		return 0, 0, newError("second argument to `%s` must be INTEGER, got %s", name, args[1].Type())
+	}
+	return absUint(a.Value), absUint(b.Value), nil
+}
+
+func absUint(x int64) uint64 {
+	if x < 0 {
+		return uint64(-(x + 1)) + 1
+	}
+	return uint64(x)
+}
+
+// gcd uses Euclid's algorithm, gcd(0, 0) is 0.
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func checkedInteger(name string, args []object.Object, value uint64) object.Object {
+	if value > math.MaxInt64 {
+		return newError("integer overflow: %s(%s, %s)", name, args[0].Inspect(), args[1].Inspect())
+	}
+	return newInteger(int64(value))
+}
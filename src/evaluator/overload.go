@@ -0,0 +1,54 @@
+package evaluator
+
+import (
+	"monkey/src/object"
+)
+
+// operatorMethods maps the infix operators a hash can overload to the key of
+// the function implementing them. For `a + b` where a is a hash holding a
+// function under "__add__", the result is a["__add__"](b). `!=` negates the
+// result of "__eq__". Operators without an entry, like `in`, cannot be
+// overloaded.
+var operatorMethods = map[string]string{
+	"+":  "__add__",
+	"-":  "__sub__",
+	"*":  "__mul__",
+	"/":  "__div__",
+	"%":  "__mod__",
+	"<":  "__lt__",
+	">":  "__gt__",
+	"==": "__eq__",
+	"!=": "__eq__",
+}
+
+// operatorMethod returns the function overloading operator for left, if left
+// is a hash that defines one. The function is bound to left as self.
+func operatorMethod(operator string, left object.Object) (object.Object, bool) {
+	hash, ok := left.(*object.Hash)
+	if !ok {
+		return nil, false
+	}
+	key, ok := operatorMethods[operator]
+	if !ok {
+		return nil, false
+	}
+
+	pair, ok := hash.Pairs[(&object.String{Value: key}).HashKey()]
+	if !ok {
+		return nil, false
+	}
+	switch pair.Value.(type) {
+	case *object.Function, *object.Builtin:
+		return bindMethod(hash, pair.Value), true
+	default:
+		return nil, false
+	}
+}
+
+func evalOverloadedInfixExpression(operator string, method, right object.Object) object.Object {
+	result := applyFunction(method, []object.Object{right})
+	if isError(result) || operator != "!=" {
+		return result
+	}
+	return nativeBoolToBooleanObject(!isTruthy(result))
+}
@@ -0,0 +1,134 @@
+package evaluator
+
+import (
+	"monkey/src/object"
+)
+
+var pathBuiltins = map[string]*object.Builtin{
+	"getIn": {
+		Signature:   "getIn(data, path) -> ANY",
+		Description: "value nested in hashes and arrays at path, null if any step is missing",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError("wrong number of arguments. got=%d, want=2", len(args))
+			}
+			path, ok := args[1].(*object.Array)
+			if !ok {
+				return newError("second argument to `getIn` must be ARRAY, got %s", args[1].Type())
+			}
+
+			current := args[0]
+			for _, step := range path.Elements {
+				current = pathStep(current, step)
+				if current == NULL {
+					return NULL
+				}
+			}
+			return current
+		},
+	},
+	"setIn": {
+		Signature:   "setIn(data, path, value) -> ANY",
+		Description: "copy of data with value set at path, creating missing hashes on the way",
+		Arity:       3,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 3 {
+				return newError("wrong number of arguments. got=%d, want=3", len(args))
+			}
+			path, ok := args[1].(*object.Array)
+			if !ok {
+				return newError("second argument to `setIn` must be ARRAY, got %s", args[1].Type())
+			}
+			return setIn(args[0], path.Elements, args[2])
+		},
+	},
+}
+
+func init() {
+	for name, builtin := range pathBuiltins {
+		builtins[name] = builtin
+	}
+}
+
+// pathStep indexes container with step like the index operator does, except
+// that a step that does not apply to the container gives null, not an error.
+func pathStep(container, step object.Object) object.Object {
+	switch container := container.(type) {
+	case *object.Hash:
+		key, ok := step.(object.Hashable)
+		if !ok {
+			return NULL
+		}
+		pair, ok := container.Pairs[key.HashKey()]
+		if !ok {
+			return NULL
+		}
+		return pair.Value
+	case *object.Array:
+		index, ok := step.(*object.Integer)
+		if !ok || index.Value < 0 || index.Value >= int64(len(container.Elements)) {
+			return NULL
+		}
+		return container.Elements[index.Value]
+	default:
+		return NULL
+	}
+}
+
+// setIn returns a copy of container with value stored at path. Only the
+// hashes and arrays along the path are copied, everything else is shared with
+// the original. A missing step or a null on the way becomes a new hash.
+func setIn(container object.Object, path []object.Object, value object.Object) object.Object {
+	if len(path) == 0 {
+		return value
+	}
+	step := path[0]
+
+	switch container := container.(type) {
+	case *object.Hash:
+		key, ok := step.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", step.Type())
+		}
+
+		var child object.Object = NULL
+		if pair, ok := container.Pairs[key.HashKey()]; ok {
+			child = pair.Value
+		}
+		child = setIn(child, path[1:], value)
+		if isError(child) {
+			return child
+		}
+
+		result := object.NewHash()
+		result.Tag = container.Tag
+		for _, pair := range container.OrderedPairs() {
+			result.Set(pair.Key.(object.Hashable).HashKey(), pair)
+		}
+		result.Set(key.HashKey(), object.HashPair{Key: step, Value: child})
+		return result
+	case *object.Array:
+		index, ok := step.(*object.Integer)
+		if !ok {
+			return newError("array index must be INTEGER, got %s", step.Type())
+		}
+		if index.Value < 0 || index.Value >= int64(len(container.Elements)) {
+			return newError("index out of range: %d", index.Value)
+		}
+
+		child := setIn(container.Elements[index.Value], path[1:], value)
+		if isError(child) {
+			return child
+		}
+
+		elements := make([]object.Object, len(container.Elements))
+		copy(elements, container.Elements)
+		elements[index.Value] = child
+		return &object.Array{Elements: elements}
+	case *object.Null:
+		return setIn(object.NewHash(), path, value)
+	default:
+		return newError("cannot set %s in %s", step.Inspect(), container.Type())
+	}
+}
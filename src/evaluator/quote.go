@@ -0,0 +1,87 @@
+package evaluator
+
+import (
+	"strconv"
+	"strings"
+
+	"monkey/src/ast"
+	"monkey/src/object"
+	"monkey/src/token"
+)
+
+// quote returns node unevaluated, except for the calls of `unquote` in it,
+// which are replaced with their evaluated argument. The replacing happens on
+// a copy, so the function or macro body holding node can be quoted again.
+func quote(node ast.Node, env *object.Environment) object.Object {
+	var failed object.Object
+
+	node = ast.Modify(ast.Copy(node), func(node ast.Node) ast.Node {
+		call, ok := node.(*ast.CallExpression)
+		if !ok || failed != nil || !isCallTo(call, "unquote") {
+			return node
+		}
+		if len(call.Arguments) != 1 {
+			failed = newError("wrong number of arguments to `unquote`. got=%d, want=1", len(call.Arguments))
+			return node
+		}
+
+		unquoted := Eval(call.Arguments[0], env)
+		if isError(unquoted) {
+			failed = unquoted
+			return node
+		}
+		converted, ok := objectToNode(unquoted)
+		if !ok {
+			failed = newError("cannot unquote %s", unquoted.Type())
+			return node
+		}
+		return converted
+	})
+
+	if failed != nil {
+		return failed
+	}
+	return &object.Quote{Node: node}
+}
+
+func isCallTo(call *ast.CallExpression, name string) bool {
+	ident, ok := call.Function.(*ast.Identifier)
+	return ok && ident.Value == name
+}
+
+// objectToNode returns the code that evaluates to obj, which must be a
+// literal value or a quote.
+func objectToNode(obj object.Object) (ast.Node, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		literal := strconv.FormatInt(obj.Value, 10)
+		return &ast.IntegerLiteral{Token: token.Token{Type: token.INT, Literal: literal}, Value: obj.Value}, true
+	case *object.Float:
+		literal := strconv.FormatFloat(obj.Value, 'g', -1, 64)
+		if !strings.ContainsAny(literal, ".eIN") {
+			literal += ".0"
+		}
+		return &ast.FloatLiteral{Token: token.Token{Type: token.FLOAT, Literal: literal}, Value: obj.Value}, true
+	case *object.String:
+		return &ast.StringLiteral{Token: token.Token{Type: token.STRING, Literal: obj.Value}, Value: obj.Value}, true
+	case *object.Boolean:
+		tok := token.Token{Type: token.FALSE, Literal: "false"}
+		if obj.Value {
+			tok = token.Token{Type: token.TRUE, Literal: "true"}
+		}
+		return &ast.Boolean{Token: tok, Value: obj.Value}, true
+	case *object.Null:
+		return &ast.NullLiteral{Token: token.Token{Type: token.NULL, Literal: "null"}}, true
+	case *object.Quote:
+		return obj.Node, true
+	default:
+		return nil, false
+	}
+}
+
+func evalQuoteCall(node *ast.CallExpression, env *object.Environment) object.Object {
+	if len(node.Arguments) != 1 {
+		return newError("wrong number of arguments to `quote`. got=%d, want=1", len(node.Arguments))
+	}
+	return quote(node.Arguments[0], env)
+}
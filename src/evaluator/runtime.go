@@ -0,0 +1,176 @@
+package evaluator
+
+import (
+	"context"
+	"slices"
+
+	"monkey/src/ast"
+	"monkey/src/object"
+)
+
+// runtime holds the settings of an evaluation that are not part of the
+// program itself. It is attached to the outermost environment, so closures
+// called from builtins see the same settings as the code around them.
+type runtime struct {
+	ctx         context.Context
+	options     Options
+	steps       int
+	allocations int
+	// evalDepth counts the calls of `eval` in progress
+	evalDepth int
+	// deferred holds a frame of deferred expressions for every function
+	// call in progress, the innermost last
+	deferred [][]deferredExpression
+}
+
+// Options bound the resources an evaluation may use and enable optional
+// instrumentation. The zero value means no limits and no instrumentation.
+type Options struct {
+	// MaxSteps is the number of AST nodes that may be evaluated before the
+	// evaluation fails with `step limit exceeded`.
+	MaxSteps int
+
+	// MaxAllocations is the total number of string bytes, array elements and
+	// hash pairs that may be created before the evaluation fails with
+	// `allocation limit exceeded`. Values returned by builtins are charged
+	// once they have been built.
+	MaxAllocations int
+
+	// Coverage, when set, records the lines of the statements executed.
+	Coverage *Coverage
+
+	// AllowSystem makes the builtins that touch the host, such as `getenv`,
+	// available. Without it, naming one of them fails as if it did not
+	// exist, so untrusted scripts stay sandboxed.
+	AllowSystem bool
+
+	// DisabledBuiltins names builtins that are removed for the evaluation,
+	// e.g. `exit` for hosted scripts. Using one fails with `identifier not
+	// found` as if it had never existed.
+	DisabledBuiltins []string
+}
+
+// SetOptions applies opts to every evaluation in env and resets the counters
+// the limits are checked against. The counters are shared by all evaluations
+// in env until the next call, so in a REPL the limits span the session.
+func SetOptions(env *object.Environment, opts Options) {
+	rt := runtimeOf(env)
+	rt.options = opts
+	rt.steps = 0
+	rt.allocations = 0
+}
+
+func runtimeOf(env *object.Environment) *runtime {
+	if rt, ok := env.Runtime().(*runtime); ok {
+		return rt
+	}
+
+	rt := &runtime{}
+	env.SetRuntime(rt)
+	return rt
+}
+
+// EvalContext evaluates node like Eval, but stops with an `evaluation
+// cancelled` error once ctx is done. The context is checked before every
+// statement, which includes every call of a user function.
+func EvalContext(ctx context.Context, node ast.Node, env *object.Environment) object.Object {
+	rt := runtimeOf(env)
+	previous := rt.ctx
+	rt.ctx = ctx
+	defer func() { rt.ctx = previous }()
+
+	return Eval(node, env)
+}
+
+func systemAllowed(env *object.Environment) bool {
+	rt, ok := env.Runtime().(*runtime)
+	return ok && rt.options.AllowSystem
+}
+
+func builtinDisabled(env *object.Environment, name string) bool {
+	rt, ok := env.Runtime().(*runtime)
+	return ok && slices.Contains(rt.options.DisabledBuiltins, name)
+}
+
+// checkRuntime returns a fatal error when the evaluation must stop, or nil.
+// It is fatal so that `try`/`catch` cannot keep a cancelled script running.
+func checkRuntime(env *object.Environment) *object.Error {
+	rt, ok := env.Runtime().(*runtime)
+	if !ok {
+		return nil
+	}
+
+	if rt.ctx != nil {
+		select {
+		case <-rt.ctx.Done():
+			return &object.Error{Message: "evaluation cancelled", Fatal: true}
+		default:
+		}
+	}
+
+	return nil
+}
+
+// visit is called for every node that is evaluated. It counts the node
+// against MaxSteps and records executed statements for Coverage.
+func visit(env *object.Environment, node ast.Node) *object.Error {
+	rt, ok := env.Runtime().(*runtime)
+	if !ok {
+		return nil
+	}
+
+	if rt.options.Coverage != nil {
+		rt.options.Coverage.mark(node)
+	}
+
+	if rt.options.MaxSteps > 0 {
+		rt.steps++
+		if rt.steps > rt.options.MaxSteps {
+			return &object.Error{Message: "step limit exceeded", Fatal: true}
+		}
+	}
+
+	return nil
+}
+
+// checkAllocation returns the fatal error of allocate if creating a value of
+// size more elements would exceed MaxAllocations, or nil. It charges nothing,
+// builtins use it to fail before building a value that the call site would
+// reject anyway.
+func checkAllocation(env *object.Environment, size int) *object.Error {
+	rt, ok := env.Runtime().(*runtime)
+	if !ok || rt.options.MaxAllocations <= 0 {
+		return nil
+	}
+
+	if size > rt.options.MaxAllocations-rt.allocations {
+		return &object.Error{Message: "allocation limit exceeded", Fatal: true}
+	}
+	return nil
+}
+
+// allocate charges the size of a newly created obj against MaxAllocations
+// and returns obj, or a fatal error once the limit is exceeded.
+func allocate(env *object.Environment, obj object.Object) object.Object {
+	rt, ok := env.Runtime().(*runtime)
+	if !ok || rt.options.MaxAllocations <= 0 {
+		return obj
+	}
+
+	switch obj := obj.(type) {
+	case *object.String:
+		rt.allocations += len(obj.Value)
+	case *object.Array:
+		rt.allocations += len(obj.Elements)
+	case *object.Hash:
+		rt.allocations += len(obj.Pairs)
+	case *object.Set:
+		rt.allocations += len(obj.Elements)
+	}
+
+	if rt.allocations > rt.options.MaxAllocations {
+		return &object.Error{Message: "allocation limit exceeded", Fatal: true}
+	}
+
+	return obj
+}
@@ -0,0 +1,247 @@
+package evaluator
+
+import (
+	"context"
+	goruntime "runtime"
+	"testing"
+	"time"
+
+	"monkey/src/lexer"
+	"monkey/src/object"
+	"monkey/src/parser"
+)
+
+func testEvalContext(ctx context.Context, input string) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	env := object.NewEnvironment()
+	return EvalContext(ctx, program, env)
+}
+
+func TestEvalContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testInspect(t, testEvalContext(ctx, "1 + 1"), "ERROR: evaluation cancelled")
+}
+
+func TestEvalContextTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	inputs := []string{
+		"loop(1000000000000, 0, fn(acc, i) { acc + i })",
+		"try { loop(1000000000000, 0, fn(acc, i) { acc + i }) } catch { 1 }",
+	}
+
+	for _, input := range inputs {
+		evaluated := testEvalContext(ctx, input)
+		err, ok := evaluated.(*object.Error)
+		if !ok || err.Message != "evaluation cancelled" || !err.Fatal {
+			t.Errorf("expected fatal cancellation error, got: %T (%+v)", evaluated, evaluated)
+		}
+	}
+}
+
+func TestEvalContextCompletes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testIntegerObject(t, testEvalContext(ctx, "let f = fn(x) { x * 2 }; f(21)"), 42)
+}
+
+func testEvalOptions(opts Options, input string) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	env := object.NewEnvironment()
+	SetOptions(env, opts)
+	return Eval(program, env)
+}
+
+func TestMaxSteps(t *testing.T) {
+	tests := []struct {
+		input    string
+		maxSteps int
+		expected string
+	}{
+		{"1 + 2", 100, "3"},
+		{"1 + 2", 0, "3"},
+		{"1 + 2", 2, "ERROR: step limit exceeded"},
+		{"let f = fn(x) { f(x) }; f(1)", 10000, "ERROR: step limit exceeded"},
+		{"loop(1000000000, 0, fn(acc, i) { acc + i })", 10000, "ERROR: step limit exceeded"},
+		{"try { loop(1000000000, 0, fn(acc, i) { acc }) } catch { 1 }", 10000, "ERROR: step limit exceeded"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEvalOptions(Options{MaxSteps: tt.maxSteps}, tt.input), tt.expected)
+	}
+}
+
+func TestMaxAllocations(t *testing.T) {
+	tests := []struct {
+		input          string
+		maxAllocations int
+		expected       string
+	}{
+		{`[1, 2, 3]`, 3, "[1, 2, 3]"},
+		{`[1, 2, 3, 4]`, 3, "ERROR: allocation limit exceeded"},
+		{`{"a": 1, "b": 2}`, 4, `{"a": 1, "b": 2}`},
+		{`"abc" + "def"`, 12, "abcdef"},
+		{`"abc" + "def"`, 11, "ERROR: allocation limit exceeded"},
+		{`repeat("ab", 100)`, 100, "ERROR: allocation limit exceeded"},
+		{`loop(1000, [], fn(acc, i) { push(acc, i) })`, 10000, "ERROR: allocation limit exceeded"},
+		{`try { repeat([1], 100) } catch { 1 }`, 10, "ERROR: allocation limit exceeded"},
+		{`repeat([1], 100)`, 0, "[" + repeatString("1", 100) + "]"},
+		{`range(1000000000000)`, 100, "ERROR: allocation limit exceeded"},
+		{`try { range(0, 9223372036854775807) } catch { 1 }`, 100, "ERROR: allocation limit exceeded"},
+		{`[1, 2]; range(8)`, 10, "[0, 1, 2, 3, 4, 5, 6, 7]"},
+		{`[1, 2, 3]; range(8)`, 10, "ERROR: allocation limit exceeded"},
+	}
+
+	for _, tt := range tests {
+		testInspect(t, testEvalOptions(Options{MaxAllocations: tt.maxAllocations}, tt.input), tt.expected)
+	}
+}
+
+func TestMaxAllocationsCheckedUpFront(t *testing.T) {
+	inputs := []string{
+		`repeat("abcdefgh", 100000000)`,
+		`repeat([1, 2, 3, 4], 100000000)`,
+		`padLeft("a", 100000000)`,
+		`padRight("a", 100000000, "xyz")`,
+		`times(100000000, fn(i) { i })`,
+		`let a = range(400); window(a, 200)`,
+		`let a = range(400); chunk(a, 1)`,
+		`let a = range(300); zip(a, a)`,
+		`let r = repeat; r("abcdefgh", 100000000)`,
+	}
+
+	for _, input := range inputs {
+		var before, after goruntime.MemStats
+		goruntime.ReadMemStats(&before)
+		evaluated := testEvalOptions(Options{MaxAllocations: 1000}, input)
+		goruntime.ReadMemStats(&after)
+
+		err, ok := evaluated.(*object.Error)
+		if !ok || err.Message != "allocation limit exceeded" || !err.Fatal {
+			t.Errorf("%q: expected fatal allocation error, got: %T (%+v)", input, evaluated, evaluated)
+		}
+		// the full results would take hundreds of megabytes
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 10<<20 {
+			t.Errorf("%q: %d bytes allocated before the limit tripped", input, allocated)
+		}
+	}
+}
+
+func repeatString(s string, n int) string {
+	out := s
+	for i := 1; i < n; i++ {
+		out += ", " + s
+	}
+	return out
+}
+
+func TestCoverage(t *testing.T) {
+	input := `let max = fn(a, b) {
+  if (a > b) {
+    return a;
+  }
+  return b;
+};
+max(1, 2);
+let unused = fn() {
+  1
+};`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	env := object.NewEnvironment()
+
+	coverage := NewCoverage()
+	SetOptions(env, Options{Coverage: coverage})
+	Eval(program, env)
+
+	expectLines(t, "covered", coverage.Covered(), []int{1, 2, 5, 7, 8})
+	expectLines(t, "uncovered", coverage.Uncovered(program), []int{3, 9})
+}
+
+func expectLines(t *testing.T, name string, got, expected []int) {
+	if len(got) != len(expected) {
+		t.Errorf("%s lines wrong, expected: %v, got: %v", name, expected, got)
+		return
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("%s lines wrong, expected: %v, got: %v", name, expected, got)
+			return
+		}
+	}
+}
+
+func TestSystemBuiltins(t *testing.T) {
+	t.Setenv("MONKEY_TEST_VAR", "banana")
+
+	system := Options{AllowSystem: true}
+	testInspect(t, testEvalOptions(system, `getenv("MONKEY_TEST_VAR")`), "banana")
+	testInspect(t, testEvalOptions(system, `getenv("MONKEY_TEST_UNSET")`), "null")
+	testInspect(t, testEvalOptions(system, `setenv("MONKEY_TEST_VAR", "kiwi"); getenv("MONKEY_TEST_VAR")`), "kiwi")
+	testInspect(t, testEvalOptions(system, `getenv(1)`), "ERROR: argument to `getenv` must be STRING, got INTEGER")
+	testInspect(t, testEvalOptions(system, `setenv("A", 1)`), "ERROR: second argument to `setenv` must be STRING, got INTEGER")
+
+	sandboxed := []string{
+		`getenv("MONKEY_TEST_VAR")`,
+		`apply(getenv, ["MONKEY_TEST_VAR"])`,
+		`setenv("MONKEY_TEST_VAR", "x")`,
+	}
+	for _, input := range sandboxed {
+		evaluated := testEvalOptions(Options{}, input)
+		err, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("expected error for %q, got: %T (%+v)", input, evaluated, evaluated)
+			continue
+		}
+		if err.Message != "`getenv` requires system access" && err.Message != "`setenv` requires system access" {
+			t.Errorf("wrong error message for %q: %q", input, err.Message)
+		}
+	}
+}
+
+func TestDisabledBuiltins(t *testing.T) {
+	opts := Options{DisabledBuiltins: []string{"exit", "len"}}
+
+	testInspect(t, testEvalOptions(opts, `exit(1)`), "ERROR: identifier not found: `exit`")
+	testInspect(t, testEvalOptions(opts, `apply(len, [[1]])`), "ERROR: identifier not found: `len`")
+	testInspect(t, testEvalOptions(opts, `first([1, 2])`), "1")
+	testInspect(t, testEvalOptions(opts, `let len = fn(x) { 0 }; len([1])`), "0")
+}
+
+func TestEvalBuiltinRespectsLimits(t *testing.T) {
+	evaluated := testEvalOptions(Options{MaxSteps: 200}, `eval("let i = 0; repeat { i = i + 1 } until (false)")`)
+	testInspect(t, evaluated, "ERROR: step limit exceeded")
+
+	evaluated = testEvalOptions(Options{DisabledBuiltins: []string{"eval"}}, `eval("1")`)
+	testInspect(t, evaluated, "ERROR: identifier not found: `eval`")
+}
+
+func TestEvalContextCancelsLoops(t *testing.T) {
+	inputs := []string{
+		"repeat {} until (false)",
+		"do {} while (true)",
+		"for (x in range(1000000)) {}",
+		"range(1000000000000)",
+	}
+
+	for _, input := range inputs {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		evaluated := testEvalContext(ctx, input)
+		cancel()
+
+		err, ok := evaluated.(*object.Error)
+		if !ok || err.Message != "evaluation cancelled" || !err.Fatal {
+			t.Errorf("%q: expected fatal cancellation error, got: %T (%+v)", input, evaluated, evaluated)
+		}
+	}
+}
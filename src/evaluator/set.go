@@ -0,0 +1,174 @@
+package evaluator
+
+import (
+	"monkey/src/object"
+)
+
+var setBuiltins = map[string]*object.Builtin{
+	"set": {
+		Signature:   "set(...) -> SET",
+		Description: "set of the given hashable values",
+		Arity:       0,
+		Variadic:    true,
+		Fn: func(args ...object.Object) object.Object {
+			set := &object.Set{Elements: make(map[object.HashKey]object.Object)}
+			for _, arg := range args {
+				if err := setInsert(set, arg); err != nil {
+					return err
+				}
+			}
+			return set
+		},
+	},
+	"add": {
+		Signature:   "add(set, x) -> SET",
+		Description: "copy of a set with x added",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			set, err := setArguments("add", args)
+			if err != nil {
+				return err
+			}
+
+			result := copySet(set)
+			if err := setInsert(result, args[1]); err != nil {
+				return err
+			}
+			return result
+		},
+	},
+	"has": {
+		Signature:   "has(set, x) -> BOOLEAN",
+		Description: "whether x is an element of a set",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			set, err := setArguments("has", args)
+			if err != nil {
+				return err
+			}
+
+			key, ok := args[1].(object.Hashable)
+			if !ok {
+				return FALSE
+			}
+			_, ok = set.Elements[key.HashKey()]
+			return nativeBoolToBooleanObject(ok)
+		},
+	},
+	"remove": {
+		Signature:   "remove(set, x) -> SET",
+		Description: "copy of a set without x",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			set, err := setArguments("remove", args)
+			if err != nil {
+				return err
+			}
+
+			result := copySet(set)
+			if key, ok := args[1].(object.Hashable); ok {
+				delete(result.Elements, key.HashKey())
+			}
+			return result
+		},
+	},
+	"union": {
+		Signature:   "union(a, b) -> SET",
+		Description: "elements that are in either set",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			return setOperation("union", args, func(inLeft, inRight bool) bool {
+				return inLeft || inRight
+			})
+		},
+	},
+	"intersect": {
+		Signature:   "intersect(a, b) -> SET",
+		Description: "elements that are in both sets",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			return setOperation("intersect", args, func(inLeft, inRight bool) bool {
+				return inLeft && inRight
+			})
+		},
+	},
+	"difference": {
+		Signature:   "difference(a, b) -> SET",
+		Description: "elements of a that are not in b",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			return setOperation("difference", args, func(inLeft, inRight bool) bool {
+				return inLeft && !inRight
+			})
+		},
+	},
+}
+
+func init() {
+	for name, builtin := range setBuiltins {
+		builtins[name] = builtin
+	}
+}
+
+func setInsert(set *object.Set, el object.Object) *object.Error {
+	key, ok := el.(object.Hashable)
+	if !ok {
+		return newError("unusable as set element: %s", el.Type())
+	}
+
+	set.Elements[key.HashKey()] = el
+	return nil
+}
+
+func copySet(set *object.Set) *object.Set {
+	elements := make(map[object.HashKey]object.Object, len(set.Elements))
+	for key, el := range set.Elements {
+		elements[key] = el
+	}
+	return &object.Set{Elements: elements}
+}
+
+// setArguments validates the (set, element) arguments of add, has and remove.
+func setArguments(name string, args []object.Object) (*object.Set, *object.Error) {
+	if len(args) != 2 {
+		return nil, newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+
+	set, ok := args[0].(*object.Set)
+	if !ok {
+		return nil, newError("first argument to `%s` must be SET, got %s", name, args[0].Type())
+	}
+
+	return set, nil
+}
+
+// setOperation builds a new set out of the elements of both arguments for
+// which keep, given the membership in either set, returns true.
+func setOperation(name string, args []object.Object, keep func(inLeft, inRight bool) bool) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+
+	left, ok := args[0].(*object.Set)
+	if !ok {
+		return newError("arguments to `%s` must be SET, got %s", name, args[0].Type())
+	}
+	right, ok := args[1].(*object.Set)
+	if !ok {
+		return newError("arguments to `%s` must be SET, got %s", name, args[1].Type())
+	}
+
+	result := &object.Set{Elements: make(map[object.HashKey]object.Object)}
+	for key, el := range left.Elements {
+		if _, inRight := right.Elements[key]; keep(true, inRight) {
+			result.Elements[key] = el
+		}
+	}
+	for key, el := range right.Elements {
+		if _, inLeft := left.Elements[key]; !inLeft && keep(false, true) {
+			result.Elements[key] = el
+		}
+	}
+
+	return result
+}
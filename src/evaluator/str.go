@@ -0,0 +1,141 @@
+package evaluator
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"monkey/src/object"
+)
+
+// strMethod is the hash key of the function that renders a hash in `str`.
+const strMethod = "__str__"
+
+// stringifying holds the hashes whose __str__ is running, so a __str__ that
+// renders its own hash gets the plain rendering instead of recursing forever.
+var stringifying = struct {
+	sync.Mutex
+	hashes map[*object.Hash]bool
+}{hashes: make(map[*object.Hash]bool)}
+
+func builtinStr(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	text, err := stringify(args[0])
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: text}
+}
+
+// stringify renders obj like Inspect, except that a hash holding a function
+// under "__str__" is rendered by calling it, also when nested in arrays and
+// hashes. Strings nested in arrays and hashes are quoted and a collection
+// nested in itself is shown as `[...]` or `{...}` where it repeats, as
+// InspectLimited does.
+func stringify(obj object.Object) (string, *object.Error) {
+	s := &stringifier{visiting: map[object.Object]bool{}}
+	return s.stringify(obj, 0)
+}
+
+type stringifier struct {
+	// visiting holds the collections enclosing the one being rendered
+	visiting map[object.Object]bool
+}
+
+func (s *stringifier) stringify(obj object.Object, depth int) (string, *object.Error) {
+	switch obj := obj.(type) {
+	case *object.Hash:
+		if fn, ok := strFunction(obj); ok && enterStr(obj) {
+			result := applyFunction(bindMethod(obj, fn), []object.Object{})
+			leaveStr(obj)
+
+			if err, ok := result.(*object.Error); ok {
+				return "", err
+			}
+			str, ok := result.(*object.String)
+			if !ok {
+				return "", newError("%s must return STRING, got %s", strMethod, result.Type())
+			}
+			return str.Value, nil
+		}
+
+		if s.visiting[obj] {
+			return "{...}", nil
+		}
+		s.visiting[obj] = true
+		defer delete(s.visiting, obj)
+
+		pairs := []string{}
+		for _, pair := range obj.OrderedPairs() {
+			key, err := s.stringify(pair.Key, depth+1)
+			if err != nil {
+				return "", err
+			}
+			value, err := s.stringify(pair.Value, depth+1)
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, key+": "+value)
+		}
+		return "{" + strings.Join(pairs, ", ") + "}", nil
+	case *object.Array:
+		if s.visiting[obj] {
+			return "[...]", nil
+		}
+		s.visiting[obj] = true
+		defer delete(s.visiting, obj)
+
+		elements := []string{}
+		for _, el := range obj.Elements {
+			text, err := s.stringify(el, depth+1)
+			if err != nil {
+				return "", err
+			}
+			elements = append(elements, text)
+		}
+		return "[" + strings.Join(elements, ", ") + "]", nil
+	case *object.String:
+		if depth > 0 {
+			return strconv.Quote(obj.Value), nil
+		}
+		return obj.Value, nil
+	default:
+		return obj.Inspect(), nil
+	}
+}
+
+func strFunction(hash *object.Hash) (object.Object, bool) {
+	pair, ok := hash.Pairs[(&object.String{Value: strMethod}).HashKey()]
+	if !ok {
+		return nil, false
+	}
+	switch pair.Value.(type) {
+	case *object.Function, *object.Builtin:
+		return pair.Value, true
+	default:
+		return nil, false
+	}
+}
+
+// enterStr marks hash as being rendered by its __str__, it reports false if
+// that is already the case.
+func enterStr(hash *object.Hash) bool {
+	stringifying.Lock()
+	defer stringifying.Unlock()
+
+	if stringifying.hashes[hash] {
+		return false
+	}
+	stringifying.hashes[hash] = true
+	return true
+}
+
+func leaveStr(hash *object.Hash) {
+	stringifying.Lock()
+	defer stringifying.Unlock()
+
+	delete(stringifying.hashes, hash)
+}
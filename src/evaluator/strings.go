@@ -0,0 +1,319 @@
+package evaluator
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"monkey/src/object"
+)
+
+var stringBuiltins = map[string]*object.Builtin{
+	"padLeft": {
+		Signature:   "padLeft(s, width, fill = \" \") -> STRING",
+		Description: "s padded on the left with fill to width runes",
+		Arity:       2,
+		Variadic:    true,
+		Fn:          unlimited(builtinPadLeft),
+	},
+	"padRight": {
+		Signature:   "padRight(s, width, fill = \" \") -> STRING",
+		Description: "s padded on the right with fill to width runes",
+		Arity:       2,
+		Variadic:    true,
+		Fn:          unlimited(builtinPadRight),
+	},
+	"lines": {
+		Signature:   "lines(s) -> ARRAY",
+		Description: "lines of s split on \\n or \\r\\n, without a trailing empty line",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			str, err := stringArgument("lines", args)
+			if err != nil {
+				return err
+			}
+			if str.Value == "" {
+				return &object.Array{Elements: []object.Object{}}
+			}
+
+			text := strings.ReplaceAll(str.Value, "\r\n", "\n")
+			return stringArray(strings.Split(strings.TrimSuffix(text, "\n"), "\n"))
+		},
+	},
+	"words": {
+		Signature:   "words(s) -> ARRAY",
+		Description: "words of s split on runs of whitespace",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			str, err := stringArgument("words", args)
+			if err != nil {
+				return err
+			}
+			return stringArray(strings.Fields(str.Value))
+		},
+	},
+	"toChars": {
+		Signature:   "toChars(s) -> ARRAY",
+		Description: "one-character strings for each rune of s",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			str, err := stringArgument("toChars", args)
+			if err != nil {
+				return err
+			}
+
+			elements := []object.Object{}
+			for _, r := range str.Value {
+				elements = append(elements, &object.String{Value: string(r)})
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"fromChars": {
+		Signature:   "fromChars(arr) -> STRING",
+		Description: "one-character strings joined back into a string",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return newError("argument to `fromChars` must be ARRAY, got %s", args[0].Type())
+			}
+
+			var out strings.Builder
+			for i, el := range arr.Elements {
+				str, ok := el.(*object.String)
+				if !ok || utf8.RuneCountInString(str.Value) != 1 {
+					return newError("element %d of `fromChars` must be a one-character STRING, got %s", i, el.Inspect())
+				}
+				out.WriteString(str.Value)
+			}
+			return &object.String{Value: out.String()}
+		},
+	},
+	"capitalize": {
+		Signature:   "capitalize(s) -> STRING",
+		Description: "s with its first letter in upper case and the rest in lower case",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return caseBuiltin("capitalize", args, capitalize)
+		},
+	},
+	"title": {
+		Signature:   "title(s) -> STRING",
+		Description: "s with every whitespace separated word capitalized",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return caseBuiltin("title", args, func(s string) string {
+				// split by hand so the whitespace between words is kept
+				var out strings.Builder
+				for len(s) > 0 {
+					end := strings.IndexFunc(s, unicode.IsSpace)
+					if end < 0 {
+						end = len(s)
+					}
+					out.WriteString(capitalize(s[:end]))
+					s = s[end:]
+
+					word := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsSpace(r) })
+					if word < 0 {
+						word = len(s)
+					}
+					out.WriteString(s[:word])
+					s = s[word:]
+				}
+				return out.String()
+			})
+		},
+	},
+	"swapCase": {
+		Signature:   "swapCase(s) -> STRING",
+		Description: "s with upper case letters in lower case and the other way round",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return caseBuiltin("swapCase", args, func(s string) string {
+				return strings.Map(func(r rune) rune {
+					if unicode.IsUpper(r) {
+						return unicode.ToLower(r)
+					}
+					return unicode.ToUpper(r)
+				}, s)
+			})
+		},
+	},
+	"equalsIgnoreCase": {
+		Signature:   "equalsIgnoreCase(a, b) -> BOOLEAN",
+		Description: "whether two strings are equal under Unicode case folding",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			return foldBuiltin("equalsIgnoreCase", args, strings.EqualFold)
+		},
+	},
+	"containsIgnoreCase": {
+		Signature:   "containsIgnoreCase(s, sub) -> BOOLEAN",
+		Description: "whether s contains sub under Unicode case folding",
+		Arity:       2,
+		Fn: func(args ...object.Object) object.Object {
+			return foldBuiltin("containsIgnoreCase", args, func(s, sub string) bool {
+				return strings.Contains(foldCase(s), foldCase(sub))
+			})
+		},
+	},
+	"md5": {
+		Signature:   "md5(s) -> STRING",
+		Description: "hex encoded MD5 digest of a string",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return digestBuiltin("md5", args, md5.New())
+		},
+	},
+	"sha256": {
+		Signature:   "sha256(s) -> STRING",
+		Description: "hex encoded SHA-256 digest of a string",
+		Arity:       1,
+		Fn: func(args ...object.Object) object.Object {
+			return digestBuiltin("sha256", args, sha256.New())
+		},
+	},
+}
+
+func init() {
+	for name, builtin := range stringBuiltins {
+		builtins[name] = builtin
+	}
+}
+
+func builtinPadLeft(env *object.Environment, args []object.Object) object.Object {
+	return padBuiltin("padLeft", env, args, func(s, padding string) string {
+		return padding + s
+	})
+}
+
+func builtinPadRight(env *object.Environment, args []object.Object) object.Object {
+	return padBuiltin("padRight", env, args, func(s, padding string) string {
+		return s + padding
+	})
+}
+
+// padBuiltin backs padLeft and padRight. The fill is repeated and cut to the
+// exact number of missing runes, then join places it on its side of s.
+func padBuiltin(name string, env *object.Environment, args []object.Object, join func(s, padding string) string) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=2 or 3", len(args))
+	}
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("first argument to `%s` must be STRING, got %s", name, args[0].Type())
+	}
+	width, ok := args[1].(*object.Integer)
+	if !ok {
+		return newError("second argument to `%s` must be INTEGER, got %s", name, args[1].Type())
+	}
+	fill := " "
+	if len(args) == 3 {
+		f, ok := args[2].(*object.String)
+		if !ok {
+			return newError("third argument to `%s` must be STRING, got %s", name, args[2].Type())
+		}
+		if f.Value == "" {
+			return newError("third argument to `%s` must not be empty", name)
+		}
+		fill = f.Value
+	}
+
+	missing := width.Value - int64(len([]rune(str.Value)))
+	if missing <= 0 {
+		return str
+	}
+
+	fillRunes := []rune(fill)
+	copies := missing/int64(len(fillRunes)) + 1
+	size, err := resultSize(name, len(fill), copies)
+	if err != nil {
+		return err
+	}
+	if err := checkAllocation(env, len(str.Value)+size); err != nil {
+		return err
+	}
+	padding := []rune(strings.Repeat(fill, int(copies)))[:missing]
+	return &object.String{Value: join(str.Value, string(padding))}
+}
+
+func stringArgument(name string, args []object.Object) (*object.String, *object.Error) {
+	if len(args) != 1 {
+		return nil, newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return nil, newError("argument to `%s` must be STRING, got %s", name, args[0].Type())
+	}
+	return str, nil
+}
+
+func stringArray(values []string) *object.Array {
+	elements := make([]object.Object, len(values))
+	for i, value := range values {
+		elements[i] = &object.String{Value: value}
+	}
+	return &object.Array{Elements: elements}
+}
+
+func digestBuiltin(name string, args []object.Object, h hash.Hash) object.Object {
+	str, err := stringArgument(name, args)
+	if err != nil {
+		return err
+	}
+
+	h.Write([]byte(str.Value))
+	return &object.String{Value: hex.EncodeToString(h.Sum(nil))}
+}
+
+func caseBuiltin(name string, args []object.Object, convert func(string) string) object.Object {
+	str, err := stringArgument(name, args)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: convert(str.Value)}
+}
+
+// foldBuiltin backs the case insensitive comparisons of two strings.
+func foldBuiltin(name string, args []object.Object, compare func(a, b string) bool) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	a, ok := args[0].(*object.String)
+	if !ok {
+		return newError("first argument to `%s` must be STRING, got %s", name, args[0].Type())
+	}
+	b, ok := args[1].(*object.String)
+	if !ok {
+		return newError("second argument to `%s` must be STRING, got %s", name, args[1].Type())
+	}
+	return nativeBoolToBooleanObject(compare(a.Value, b.Value))
+}
+
+// foldCase maps every rune of s to the smallest rune it folds to, so two
+// strings equal under strings.EqualFold fold to the same string.
+func foldCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		folded := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			folded = min(folded, f)
+		}
+		return folded
+	}, s)
+}
+
+func capitalize(s string) string {
+	runes := []rune(strings.ToLower(s))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
@@ -0,0 +1,88 @@
+package evaluator
+
+import (
+	"monkey/src/ast"
+	"monkey/src/object"
+)
+
+func evalStructStatement(node *ast.StructStatement, env *object.Environment) object.Object {
+	st := &object.Struct{
+		Name:    node.Name.Value,
+		Methods: make(map[string]*object.Function),
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range node.Fields {
+		if seen[field.Value] {
+			return newError("duplicate member `%s` in struct %s", field.Value, st.Name)
+		}
+		seen[field.Value] = true
+		st.Fields = append(st.Fields, field.Value)
+	}
+	for _, method := range node.Methods {
+		if seen[method.Name.Value] {
+			return newError("duplicate member `%s` in struct %s", method.Name.Value, st.Name)
+		}
+		seen[method.Name.Value] = true
+		st.Methods[method.Name.Value] = &object.Function{
+			Parameters: method.Function.Parameters,
+			Body:       method.Function.Body,
+			Env:        env,
+		}
+	}
+
+	env.Set(st.Name, st)
+	return nil
+}
+
+// evalMemberExpression reads a field or method of an instance, or the value
+// under a string key of a hash. Methods, and functions stored in a hash, are
+// bound to the receiver right here, so `self` refers to it once called, also
+// when the result is stored and called later. A function called any other
+// way, like `h["f"]()`, has no receiver and `self` is not defined in it.
+func evalMemberExpression(node *ast.MemberExpression, env *object.Environment) object.Object {
+	obj := Eval(node.Object, env)
+	if isError(obj) {
+		return obj
+	}
+
+	name := node.Property.Value
+	switch obj := obj.(type) {
+	case *object.Instance:
+		if value, ok := obj.Fields[name]; ok {
+			return value
+		}
+		if method, ok := obj.Struct.Methods[name]; ok {
+			return &object.BoundMethod{Receiver: obj, Method: method}
+		}
+		return newError("%s has no member `%s`", obj.Struct.Name, name)
+	case *object.Hash:
+		pair, ok := obj.Pairs[(&object.String{Value: name}).HashKey()]
+		if !ok {
+			return NULL
+		}
+		return bindMethod(obj, pair.Value)
+	default:
+		return newError("member access not supported: %s", obj.Type())
+	}
+}
+
+// bindMethod binds receiver to self in value if it is a function.
+func bindMethod(receiver, value object.Object) object.Object {
+	if fn, ok := value.(*object.Function); ok {
+		return &object.BoundMethod{Receiver: receiver, Method: fn}
+	}
+	return value
+}
+
+func newInstance(st *object.Struct, args []object.Object) object.Object {
+	if len(args) != len(st.Fields) {
+		return newError("wrong number of arguments. got=%d, want=%d", len(args), len(st.Fields))
+	}
+
+	instance := &object.Instance{Struct: st, Fields: make(map[string]object.Object, len(args))}
+	for i, name := range st.Fields {
+		instance.Fields[name] = args[i]
+	}
+	return instance
+}
@@ -0,0 +1,60 @@
+package evaluator
+
+import (
+	"os"
+
+	"monkey/src/object"
+)
+
+// systemBuiltins give scripts access to the host. They are marked System and
+// only resolve when the evaluation was started with AllowSystem.
+var systemBuiltins = map[string]*object.Builtin{
+	"getenv": {
+		Signature:   "getenv(name) -> STRING|NULL",
+		Description: "value of an environment variable, null if it is unset",
+		Arity:       1,
+		System:      true,
+		Fn: func(args ...object.Object) object.Object {
+			name, err := stringArgument("getenv", args)
+			if err != nil {
+				return err
+			}
+
+			value, ok := os.LookupEnv(name.Value)
+			if !ok {
+				return NULL
+			}
+			return &object.String{Value: value}
+		},
+	},
+	"setenv": {
+		Signature:   "setenv(name, value) -> NULL",
+		Description: "set an environment variable of the running process",
+		Arity:       2,
+		System:      true,
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError("wrong number of arguments. got=%d, want=2", len(args))
+			}
+			name, ok := args[0].(*object.String)
+			if !ok {
+				return newError("first argument to `setenv` must be STRING, got %s", args[0].Type())
+			}
+			value, ok := args[1].(*object.String)
+			if !ok {
+				return newError("second argument to `setenv` must be STRING, got %s", args[1].Type())
+			}
+
+			if err := os.Setenv(name.Value, value.Value); err != nil {
+				return newError("setenv: %s", err)
+			}
+			return NULL
+		},
+	},
+}
+
+func init() {
+	for name, builtin := range systemBuiltins {
+		builtins[name] = builtin
+	}
+}
@@ -0,0 +1,88 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"monkey/src/object"
+)
+
+// builtinTable prints an array of hashes as an ASCII table. The columns are
+// the keys of all rows in the order they are first seen; a row without a key
+// gets an empty cell.
+func builtinTable(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `table` must be ARRAY, got %s", args[0].Type())
+	}
+
+	var columns []object.HashKey
+	headers := make(map[object.HashKey]string)
+	rows := make([]*object.Hash, len(arr.Elements))
+	for i, el := range arr.Elements {
+		row, ok := el.(*object.Hash)
+		if !ok {
+			return newError("elements of `table` must be HASH, got %s", el.Type())
+		}
+		rows[i] = row
+
+		for _, pair := range row.OrderedPairs() {
+			key := pair.Key.(object.Hashable).HashKey()
+			if _, ok := headers[key]; !ok {
+				headers[key] = pair.Key.Inspect()
+				columns = append(columns, key)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return NULL
+	}
+
+	cells := make([][]string, len(rows)+1)
+	cells[0] = make([]string, len(columns))
+	for j, key := range columns {
+		cells[0][j] = headers[key]
+	}
+	for i, row := range rows {
+		cells[i+1] = make([]string, len(columns))
+		for j, key := range columns {
+			if pair, ok := row.Pairs[key]; ok {
+				cells[i+1][j] = pair.Value.Inspect()
+			}
+		}
+	}
+
+	widths := make([]int, len(columns))
+	for _, line := range cells {
+		for j, cell := range line {
+			widths[j] = max(widths[j], utf8.RuneCountInString(cell))
+		}
+	}
+
+	separator := "+"
+	for _, width := range widths {
+		separator += strings.Repeat("-", width+2) + "+"
+	}
+
+	var out strings.Builder
+	out.WriteString(separator + "\n")
+	for i, line := range cells {
+		out.WriteString("|")
+		for j, cell := range line {
+			padding := strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cell))
+			out.WriteString(" " + cell + padding + " |")
+		}
+		out.WriteString("\n")
+		if i == 0 {
+			out.WriteString(separator + "\n")
+		}
+	}
+	out.WriteString(separator + "\n")
+
+	fmt.Fprint(Output, out.String())
+	return NULL
+}
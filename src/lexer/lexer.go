@@ -1,6 +1,10 @@
 package lexer
 
 import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"monkey/src/token"
 )
 
@@ -8,38 +12,66 @@ type Lexer struct {
 	input        string
 	position     int
 	readPosition int
-	ch           byte
+	ch           rune
+	line         int
+	column       int
+	// operandEnd is the position right after the last identifier, `)` or
+	// `]`. Only there `++` and `--` are postfix operators, elsewhere they
+	// are two signs, as in `5--3` or `--5`.
+	operandEnd int
 }
 
 func New(input string) *Lexer {
 	l := &Lexer{
-		input: input,
+		input:      input,
+		line:       1,
+		operandEnd: -1,
 	}
 	l.readChar()
 	return l
 }
 
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
 	} else {
-		return l.input[l.readPosition]
+		ch, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+		return ch
 	}
 }
 
+// readChar decodes the next rune of the input, so positions are byte offsets
+// but multibyte characters are never split.
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	l.column++
+
+	width := 1
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
-		l.ch = l.input[l.readPosition]
+		l.ch, width = utf8.DecodeRuneInString(l.input[l.readPosition:])
 	}
 	l.position = l.readPosition
-	l.readPosition++
+	l.readPosition += width
 }
 
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
 	l.skipWhiteSpace()
+
+	line, column := l.line, l.column
+	tok := l.nextToken()
+	tok.Line = line
+	tok.Column = column
+
+	return tok
+}
+
+func (l *Lexer) nextToken() token.Token {
+	var tok token.Token
 	switch l.ch {
 	case '=':
 		if l.peekChar() == '=' {
@@ -71,14 +103,27 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.LPAREN, l.ch)
 	case ')':
 		tok = newToken(token.RPAREN, l.ch)
+		l.operandEnd = l.readPosition
 	case '+':
-		tok = newToken(token.PLUS, l.ch)
+		if l.peekChar() == '+' && l.position == l.operandEnd {
+			l.readChar()
+			tok = token.Token{Type: token.INCREMENT, Literal: "++"}
+		} else {
+			tok = newToken(token.PLUS, l.ch)
+		}
 	case '-':
-		tok = newToken(token.MINUS, l.ch)
+		if l.peekChar() == '-' && l.position == l.operandEnd {
+			l.readChar()
+			tok = token.Token{Type: token.DECREMENT, Literal: "--"}
+		} else {
+			tok = newToken(token.MINUS, l.ch)
+		}
 	case '*':
 		tok = newToken(token.ASTERISK, l.ch)
 	case '/':
 		tok = newToken(token.SLASH, l.ch)
+	case '%':
+		tok = newToken(token.PERCENT, l.ch)
 	case '<':
 		tok = newToken(token.LT, l.ch)
 	case '>':
@@ -86,12 +131,29 @@ func (l *Lexer) NextToken() token.Token {
 	case '"':
 		tok.Type = token.STRING
 		tok.Literal = l.readString()
+	case '`':
+		if literal, ok := l.readRawString(); ok {
+			tok.Type = token.STRING
+			tok.Literal = literal
+		} else {
+			tok.Type = token.ILLEGAL
+			tok.Literal = "unterminated raw string"
+		}
 	case '[':
 		tok = newToken(token.LBRACKET, l.ch)
 	case ']':
 		tok = newToken(token.RBRACKET, l.ch)
+		l.operandEnd = l.readPosition
 	case ':':
 		tok = newToken(token.COLON, l.ch)
+	case '.':
+		if strings.HasPrefix(l.input[l.position:], "...") {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		} else {
+			tok = newToken(token.DOT, l.ch)
+		}
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
@@ -99,10 +161,12 @@ func (l *Lexer) NextToken() token.Token {
 		if isLetter(l.ch) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookUpIdent(tok.Literal)
+			if tok.Type == token.IDENT {
+				l.operandEnd = l.position
+			}
 			return tok
 		} else if isDigit(l.ch) {
-			tok.Literal = l.readNumber()
-			tok.Type = token.INT
+			tok.Literal, tok.Type = l.readNumber()
 			return tok
 		} else {
 			tok = newToken(token.ILLEGAL, l.ch)
@@ -112,45 +176,94 @@ func (l *Lexer) NextToken() token.Token {
 	return tok
 }
 
-func newToken(tokenType token.TokenType, ch byte) token.Token {
+func newToken(tokenType token.TokenType, ch rune) token.Token {
 	return token.Token{
 		Type:    tokenType,
 		Literal: string(ch),
 	}
 }
 
+// readString reads a double quoted string, processing the escape sequences
+// \n, \t, \r, \" and \\. Any other backslash is kept as is.
 func (l *Lexer) readString() string {
-	position := l.position + 1
+	var out strings.Builder
 	for {
 		l.readChar()
 		if l.ch == '"' || l.ch == 0 {
 			break
 		}
+
+		if l.ch == '\\' {
+			switch l.peekChar() {
+			case 'n':
+				l.readChar()
+				out.WriteRune('\n')
+				continue
+			case 't':
+				l.readChar()
+				out.WriteRune('\t')
+				continue
+			case 'r':
+				l.readChar()
+				out.WriteRune('\r')
+				continue
+			case '"', '\\':
+				l.readChar()
+			}
+		}
+		out.WriteRune(l.ch)
+	}
+	return out.String()
+}
+
+// readRawString reads a backtick delimited string verbatim, without any
+// escape processing. It reports false if the input ends before the closing
+// backtick.
+func (l *Lexer) readRawString() (string, bool) {
+	position := l.position + 1
+	for {
+		l.readChar()
+		if l.ch == '`' {
+			return l.input[position:l.position], true
+		}
+		if l.ch == 0 {
+			return "", false
+		}
 	}
-	return l.input[position:l.position]
 }
 
 func (l *Lexer) readIdentifier() string {
 	pos := l.position
-	for isLetter(l.ch) {
+	for isLetter(l.ch) || unicode.IsDigit(l.ch) {
 		l.readChar()
 	}
 	return l.input[pos:l.position]
 }
 
-func (l *Lexer) readNumber() string {
+// readNumber reads an integer, or a float when the digits are followed by a
+// dot and at least one more digit.
+func (l *Lexer) readNumber() (string, token.TokenType) {
 	pos := l.position
 	for isDigit(l.ch) {
 		l.readChar()
 	}
-	return l.input[pos:l.position]
+
+	if l.ch != '.' || !isDigit(l.peekChar()) {
+		return l.input[pos:l.position], token.INT
+	}
+
+	l.readChar()
+	for isDigit(l.ch) {
+		l.readChar()
+	}
+	return l.input[pos:l.position], token.FLOAT
 }
 
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '_'
 }
 
-func isDigit(ch byte) bool {
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
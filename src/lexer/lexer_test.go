@@ -142,3 +142,274 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	input := `let λ = fn(café) { café + π2 }; Σ_1 != 0;`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LET, "let"},
+		{token.IDENT, "λ"},
+		{token.ASSIGN, "="},
+		{token.FUNCTION, "fn"},
+		{token.LPAREN, "("},
+		{token.IDENT, "café"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "café"},
+		{token.PLUS, "+"},
+		{token.IDENT, "π2"},
+		{token.RBRACE, "}"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "Σ_1"},
+		{token.NOT_EQ, "!="},
+		{token.INT, "0"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, token := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != token.expectedType {
+			t.Fatalf("Test [%d] type failed. Expected: %q, got: %q", i, token.expectedType, tok.Type)
+		}
+		if tok.Literal != token.expectedLiteral {
+			t.Fatalf("Test [%d] literal failed. Expected: %q, got: %q", i, token.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestTokenPositions(t *testing.T) {
+	input := `let café = "héllo";
+  λ + 1;
+"ü" ü`
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"let", 1, 1},
+		{"café", 1, 5},
+		{"=", 1, 10},
+		{"héllo", 1, 12},
+		{";", 1, 19},
+		{"λ", 2, 3},
+		{"+", 2, 5},
+		{"1", 2, 7},
+		{";", 2, 8},
+		{"ü", 3, 1},
+		{"ü", 3, 5},
+		{"", 3, 6},
+	}
+
+	lexer := New(input)
+
+	for i, tt := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("Test [%d] literal failed. Expected: %q, got: %q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("Test [%d] position failed. Expected: %d:%d, got: %d:%d",
+				i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
+
+func TestMultibyteIllegalCharacter(t *testing.T) {
+	lexer := New("€")
+
+	tok := lexer.NextToken()
+	if tok.Type != token.ILLEGAL || tok.Literal != "€" {
+		t.Fatalf("Expected ILLEGAL %q, got: %q %q", "€", tok.Type, tok.Literal)
+	}
+
+	tok = lexer.NextToken()
+	if tok.Type != token.EOF {
+		t.Fatalf("Expected EOF, got: %q", tok.Type)
+	}
+}
+
+func TestStringLiterals(t *testing.T) {
+	input := "\"a\\tb\\n\\\"c\\\"\\\\\" `C:\\dir\\n\n\"raw\"` \"\\d\" `unterminated"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRING, "a\tb\n\"c\"\\"},
+		{token.STRING, "C:\\dir\\n\n\"raw\""},
+		{token.STRING, "\\d"},
+		{token.ILLEGAL, "unterminated raw string"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tt := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("Test [%d] type failed. Expected: %q, got: %q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("Test [%d] literal failed. Expected: %q, got: %q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestStructTokens(t *testing.T) {
+	input := `struct Point { x } p.x`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRUCT, "struct"},
+		{token.IDENT, "Point"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "x"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "p"},
+		{token.DOT, "."},
+		{token.IDENT, "x"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tt := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("Test [%d] type failed. Expected: %q, got: %q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("Test [%d] literal failed. Expected: %q, got: %q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestFloatLiterals(t *testing.T) {
+	input := `3.14 0.5 10 1.x [1...]`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FLOAT, "3.14"},
+		{token.FLOAT, "0.5"},
+		{token.INT, "10"},
+		{token.INT, "1"},
+		{token.DOT, "."},
+		{token.IDENT, "x"},
+		{token.LBRACKET, "["},
+		{token.INT, "1"},
+		{token.ELLIPSIS, "..."},
+		{token.RBRACKET, "]"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tt := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("Test [%d] type failed. Expected: %q, got: %q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("Test [%d] literal failed. Expected: %q, got: %q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestLoopTokens(t *testing.T) {
+	input := `repeat { break; continue } until (x)`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "repeat"},
+		{token.LBRACE, "{"},
+		{token.BREAK, "break"},
+		{token.SEMICOLON, ";"},
+		{token.CONTINUE, "continue"},
+		{token.RBRACE, "}"},
+		{token.UNTIL, "until"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tt := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("Test [%d] type failed. Expected: %q, got: %q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("Test [%d] literal failed. Expected: %q, got: %q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestIncrementTokens(t *testing.T) {
+	input := `i++ - -j-- 5--3 --x a[0]++ f()-- x ++`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "i"},
+		{token.INCREMENT, "++"},
+		{token.MINUS, "-"},
+		{token.MINUS, "-"},
+		{token.IDENT, "j"},
+		{token.DECREMENT, "--"},
+		{token.INT, "5"},
+		{token.MINUS, "-"},
+		{token.MINUS, "-"},
+		{token.INT, "3"},
+		{token.MINUS, "-"},
+		{token.MINUS, "-"},
+		{token.IDENT, "x"},
+		{token.IDENT, "a"},
+		{token.LBRACKET, "["},
+		{token.INT, "0"},
+		{token.RBRACKET, "]"},
+		{token.INCREMENT, "++"},
+		{token.IDENT, "f"},
+		{token.LPAREN, "("},
+		{token.RPAREN, ")"},
+		{token.DECREMENT, "--"},
+		{token.IDENT, "x"},
+		{token.PLUS, "+"},
+		{token.PLUS, "+"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tt := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("Test [%d] type failed. Expected: %q, got: %q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("Test [%d] literal failed. Expected: %q, got: %q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
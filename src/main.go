@@ -8,6 +8,15 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		source, err := os.ReadFile(os.Args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(repl.Run(string(source), os.Args[2:], os.Stderr))
+	}
+
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
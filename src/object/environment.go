@@ -1,8 +1,14 @@
 package object
 
+import (
+	"maps"
+	"sort"
+)
+
 type Environment struct {
-	pool  map[string]Object
-	outer *Environment
+	pool    map[string]Object
+	outer   *Environment
+	runtime any
 }
 
 func NewEnvironment() *Environment {
@@ -24,6 +30,85 @@ func (env *Environment) Set(name string, val Object) Object {
 	return val
 }
 
+// Assign rebinds name in the innermost environment of the chain that already
+// binds it, so a block can update a variable of an enclosing scope. It
+// reports false, changing nothing, when name is not bound at all.
+func (env *Environment) Assign(name string, val Object) (Object, bool) {
+	for e := env; e != nil; e = e.outer {
+		if _, ok := e.pool[name]; ok {
+			e.pool[name] = val
+			return val, true
+		}
+	}
+	return nil, false
+}
+
+// Snapshot is a copy of the bindings of an environment, taken with
+// Environment.Snapshot to be put back with Environment.Restore.
+type Snapshot struct {
+	pool map[string]Object
+}
+
+// Snapshot copies the bindings of env itself, not those of the environments
+// it is enclosed in. The copy is shallow: the values are shared, so Restore
+// undoes rebinding a name but not changes made to an array or hash in place.
+func (env *Environment) Snapshot() Snapshot {
+	return Snapshot{pool: maps.Clone(env.pool)}
+}
+
+// Restore replaces the bindings of env with those of s, discarding every
+// binding made or changed since. s stays valid and can be restored again.
+func (env *Environment) Restore(s Snapshot) {
+	env.pool = maps.Clone(s.pool)
+	if env.pool == nil {
+		env.pool = make(map[string]Object)
+	}
+}
+
+// Keys returns the sorted names visible from env, its own bindings and those
+// of the environments it is enclosed in.
+func (env *Environment) Keys() []string {
+	seen := make(map[string]bool)
+	for e := env; e != nil; e = e.outer {
+		for name := range e.pool {
+			seen[name] = true
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for name := range seen {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Global returns the outermost environment of the chain env belongs to.
+func (env *Environment) Global() *Environment {
+	for env.outer != nil {
+		env = env.outer
+	}
+	return env
+}
+
+// SetGlobal binds name in the outermost environment instead of env itself.
+// An enclosed environment that has its own binding for name keeps shadowing
+// the global one: Get still finds the innermost binding first.
+func (env *Environment) SetGlobal(name string, val Object) Object {
+	return env.Global().Set(name, val)
+}
+
+// Runtime returns the evaluator state attached to the outermost environment,
+// so every scope of a program shares the same settings.
+func (env *Environment) Runtime() any {
+	return env.Global().runtime
+}
+
+// SetRuntime attaches evaluator state to the outermost environment.
+func (env *Environment) SetRuntime(runtime any) {
+	env.Global().runtime = runtime
+}
+
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
@@ -0,0 +1,107 @@
+package object
+
+import "bytes"
+
+// Equal reports whether a and b are structurally equal. Values of different
+// types are never equal. Scalars and bytes compare by value, arrays
+// element-wise, hashes by their tag and pair-wise regardless of their order,
+// sets by their elements and struct instances field by field. Errors, return
+// values and exits compare by what they carry, and functions and builtins
+// only equal themselves. Cyclic values are equal when they have the same
+// shape: a pair of collections met again while being compared is taken as
+// equal, so the comparison ends.
+func Equal(a, b Object) bool {
+	return equal(a, b, map[[2]Object]bool{})
+}
+
+// comparing holds the pairs of collections enclosing the pair being compared
+func equal(a, b Object, comparing map[[2]Object]bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.(type) {
+	case *Array, *Hash, *Instance:
+		pair := [2]Object{a, b}
+		if comparing[pair] {
+			return true
+		}
+		comparing[pair] = true
+		defer delete(comparing, pair)
+	}
+
+	switch a := a.(type) {
+	case *Integer:
+		return a.Value == b.(*Integer).Value
+	case *Float:
+		return a.Value == b.(*Float).Value
+	case *Boolean:
+		return a.Value == b.(*Boolean).Value
+	case *String:
+		return a.Value == b.(*String).Value
+	case *Null:
+		return true
+	case *Bytes:
+		return bytes.Equal(a.Value, b.(*Bytes).Value)
+	case *Array:
+		other := b.(*Array)
+		if len(a.Elements) != len(other.Elements) {
+			return false
+		}
+		for i, el := range a.Elements {
+			if !equal(el, other.Elements[i], comparing) {
+				return false
+			}
+		}
+		return true
+	case *Hash:
+		other := b.(*Hash)
+		if a.Tag != other.Tag || len(a.Pairs) != len(other.Pairs) {
+			return false
+		}
+		for key, pair := range a.Pairs {
+			otherPair, ok := other.Pairs[key]
+			if !ok || !equal(pair.Value, otherPair.Value, comparing) {
+				return false
+			}
+		}
+		return true
+	case *Set:
+		other := b.(*Set)
+		if len(a.Elements) != len(other.Elements) {
+			return false
+		}
+		for key := range a.Elements {
+			if _, ok := other.Elements[key]; !ok {
+				return false
+			}
+		}
+		return true
+	case *Error:
+		other := b.(*Error)
+		return a.Message == other.Message && a.Fatal == other.Fatal
+	case *ReturnValue:
+		return equal(a.Value, b.(*ReturnValue).Value, comparing)
+	case *Exit:
+		return a.Code == b.(*Exit).Code
+	case *Instance:
+		other := b.(*Instance)
+		if a.Struct != other.Struct {
+			return false
+		}
+		for name, value := range a.Fields {
+			if !equal(value, other.Fields[name], comparing) {
+				return false
+			}
+		}
+		return true
+	case *BoundMethod:
+		other, ok := b.(*BoundMethod)
+		return ok && a.Receiver == other.Receiver && a.Method == other.Method
+	default:
+		return a == b
+	}
+}
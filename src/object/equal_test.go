@@ -0,0 +1,76 @@
+package object
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	hash := func(pairs ...Object) *Hash {
+		h := NewHash()
+		for i := 0; i < len(pairs); i += 2 {
+			h.Set(pairs[i].(Hashable).HashKey(), HashPair{Key: pairs[i], Value: pairs[i+1]})
+		}
+		return h
+	}
+	set := func(elements ...Object) *Set {
+		s := &Set{Elements: make(map[HashKey]Object)}
+		for _, el := range elements {
+			s.Elements[el.(Hashable).HashKey()] = el
+		}
+		return s
+	}
+	integer := func(v int64) *Integer { return &Integer{Value: v} }
+	str := func(v string) *String { return &String{Value: v} }
+	fn := &Function{}
+	builtin := &Builtin{}
+
+	tests := []struct {
+		name     string
+		a, b     Object
+		expected bool
+	}{
+		{"equal integers", integer(1), integer(1), true},
+		{"different integers", integer(1), integer(2), false},
+		{"integer and float", integer(1), &Float{Value: 1}, false},
+		{"equal floats", &Float{Value: 1.5}, &Float{Value: 1.5}, true},
+		{"NaN", &Float{Value: math.NaN()}, &Float{Value: math.NaN()}, false},
+		{"equal booleans", &Boolean{Value: true}, &Boolean{Value: true}, true},
+		{"different booleans", &Boolean{Value: true}, &Boolean{Value: false}, false},
+		{"equal strings", str("a"), str("a"), true},
+		{"different strings", str("a"), str("b"), false},
+		{"string and integer", str("1"), integer(1), false},
+		{"nulls", &Null{}, &Null{}, true},
+		{"null and false", &Null{}, &Boolean{Value: false}, false},
+		{"equal bytes", &Bytes{Value: []byte("ab")}, &Bytes{Value: []byte("ab")}, true},
+		{"different bytes", &Bytes{Value: []byte("ab")}, &Bytes{Value: []byte("a")}, false},
+		{"equal arrays", &Array{Elements: []Object{integer(1), str("a")}}, &Array{Elements: []Object{integer(1), str("a")}}, true},
+		{"arrays of different length", &Array{Elements: []Object{integer(1)}}, &Array{}, false},
+		{"nested arrays", &Array{Elements: []Object{&Array{Elements: []Object{integer(1)}}}}, &Array{Elements: []Object{&Array{Elements: []Object{integer(2)}}}}, false},
+		{"hashes in different order", hash(str("a"), integer(1), str("b"), integer(2)), hash(str("b"), integer(2), str("a"), integer(1)), true},
+		{"hashes with different values", hash(str("a"), integer(1)), hash(str("a"), integer(2)), false},
+		{"hashes with different keys", hash(str("a"), integer(1)), hash(str("b"), integer(1)), false},
+		{"nested hashes", hash(str("a"), hash(str("b"), integer(1))), hash(str("a"), hash(str("b"), integer(1))), true},
+		{"equal sets", set(integer(1), integer(2)), set(integer(2), integer(1)), true},
+		{"different sets", set(integer(1)), set(integer(2)), false},
+		{"equal errors", &Error{Message: "x"}, &Error{Message: "x"}, true},
+		{"fatal and plain error", &Error{Message: "x", Fatal: true}, &Error{Message: "x"}, false},
+		{"return values", &ReturnValue{Value: integer(1)}, &ReturnValue{Value: integer(1)}, true},
+		{"exits", &Exit{Code: 1}, &Exit{Code: 2}, false},
+		{"same function", fn, fn, true},
+		{"different functions", fn, &Function{}, false},
+		{"same builtin", builtin, builtin, true},
+		{"different builtins", builtin, &Builtin{}, false},
+		{"nil and nil", nil, nil, true},
+		{"nil and null", nil, &Null{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.expected {
+			t.Errorf("%s: Equal(a, b) = %t, expected %t", tt.name, got, tt.expected)
+		}
+		if got := Equal(tt.b, tt.a); got != tt.expected {
+			t.Errorf("%s: Equal(b, a) = %t, expected %t", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package object
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InspectLimited renders obj like Inspect, but shows at most maxLen elements
+// of each array, hash, set or bytes value and collections nested no deeper
+// than maxDepth; what is left out is shown as `...`. A limit of zero or less
+// means no limit. A collection nested in itself is cut off the same way where
+// it repeats, so cyclic values render in finite space. Strings inside a
+// collection are quoted and escaped so `["a, b"]` and `["a", "b"]` can be
+// told apart; obj itself is rendered unquoted when it is a string.
+func InspectLimited(obj Object, maxLen, maxDepth int) string {
+	l := &limitedInspector{maxLen: maxLen, maxDepth: maxDepth, visiting: map[Object]bool{}}
+	return l.inspect(obj, 0)
+}
+
+type limitedInspector struct {
+	maxLen, maxDepth int
+	// visiting holds the collections enclosing the one being rendered
+	visiting map[Object]bool
+}
+
+func (l *limitedInspector) inspect(obj Object, depth int) string {
+	nested := (l.maxDepth > 0 && depth >= l.maxDepth) || l.visiting[obj]
+
+	switch obj := obj.(type) {
+	case *Array:
+		if nested {
+			return "[...]"
+		}
+		l.visiting[obj] = true
+		defer delete(l.visiting, obj)
+
+		elements := []string{}
+		for _, el := range obj.Elements {
+			elements = append(elements, l.inspect(el, depth+1))
+		}
+		return "[" + joinLimited(elements, l.maxLen) + "]"
+	case *Hash:
+		if nested {
+			return "{...}"
+		}
+		l.visiting[obj] = true
+		defer delete(l.visiting, obj)
+
+		pairs := []string{}
+		for _, pair := range obj.OrderedPairs() {
+			pairs = append(pairs, l.inspect(pair.Key, depth+1)+": "+l.inspect(pair.Value, depth+1))
+		}
+		return "{" + joinLimited(pairs, l.maxLen) + "}"
+	case *Instance:
+		if nested {
+			return obj.Struct.Name + "(...)"
+		}
+		l.visiting[obj] = true
+		defer delete(l.visiting, obj)
+
+		fields := []string{}
+		for _, name := range obj.Struct.Fields {
+			fields = append(fields, name+": "+l.inspect(obj.Fields[name], depth+1))
+		}
+		return obj.Struct.Name + "(" + joinLimited(fields, l.maxLen) + ")"
+	case *Set:
+		if nested {
+			return "set(...)"
+		}
+		elements := []string{}
+		for _, el := range obj.Elements {
+			elements = append(elements, l.inspect(el, depth+1))
+		}
+		// Map iteration order is random, sort so the output is stable.
+		sort.Strings(elements)
+		return "set(" + joinLimited(elements, l.maxLen) + ")"
+	case *String:
+		if depth > 0 {
+			return strconv.Quote(obj.Value)
+		}
+		return obj.Value
+	case *Bytes:
+		if l.maxLen <= 0 || len(obj.Value) <= l.maxLen {
+			return obj.Inspect()
+		}
+		return strings.TrimSuffix((&Bytes{Value: obj.Value[:l.maxLen]}).Inspect(), ")") + ", ...)"
+	default:
+		return obj.Inspect()
+	}
+}
+
+func joinLimited(elements []string, maxLen int) string {
+	if maxLen > 0 && len(elements) > maxLen {
+		elements = append(elements[:maxLen:maxLen], "...")
+	}
+	return strings.Join(elements, ", ")
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
 
 	"monkey/src/ast"
@@ -28,6 +31,43 @@ func (i *Integer) Type() ObjectType {
 	return INTEGER_OBJ
 }
 
+type Float struct {
+	Value float64
+}
+
+// FloatPrecision is the number of significant digits Float.Inspect shows,
+// with trailing zeros trimmed, so `0.1 + 0.2` displays as 0.3. Only the
+// display is affected, the value keeps its full precision. Zero or less
+// shows the shortest representation that reads back as the same float.
+var FloatPrecision = 15
+
+// Inspect always shows a decimal point or exponent so floats are told apart
+// from integers, and spells the IEEE special values NaN and (-)Infinity.
+func (f *Float) Inspect() string {
+	switch {
+	case math.IsNaN(f.Value):
+		return "NaN"
+	case math.IsInf(f.Value, 1):
+		return "Infinity"
+	case math.IsInf(f.Value, -1):
+		return "-Infinity"
+	}
+
+	precision := FloatPrecision
+	if precision <= 0 {
+		precision = -1
+	}
+	s := strconv.FormatFloat(f.Value, 'g', precision, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+func (f *Float) Type() ObjectType {
+	return FLOAT_OBJ
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -52,8 +92,41 @@ func (rv *ReturnValue) Inspect() string {
 	return rv.Value.Inspect()
 }
 
+// Break and Continue are the results of `break` and `continue`. They unwind
+// the blocks of a loop body up to the loop, which then stops or moves on to
+// the next iteration.
+type Break struct{}
+
+func (b *Break) Type() ObjectType { return BREAK_OBJ }
+func (b *Break) Inspect() string  { return "break" }
+
+type Continue struct{}
+
+func (c *Continue) Type() ObjectType { return CONTINUE_OBJ }
+func (c *Continue) Inspect() string  { return "continue" }
+
+// Exit is returned by the `exit` builtin. It unwinds the evaluation like an
+// error that cannot be caught, so the runner gets to see Code.
+type Exit struct {
+	Code int64
+}
+
+func (e *Exit) Type() ObjectType { return EXIT_OBJ }
+
+func (e *Exit) Inspect() string {
+	return fmt.Sprintf("exit(%d)", e.Code)
+}
+
+// Error is the result of a failed evaluation. A Fatal error, as raised by
+// `panic`, is not caught by `try`/`catch` and always reaches the top level.
+// Line and Column locate the expression that failed, they are zero while
+// unknown. Trace describes the calls the error left, innermost first.
 type Error struct {
 	Message string
+	Fatal   bool
+	Line    int
+	Column  int
+	Trace   []string
 }
 
 func (eo *Error) Type() ObjectType {
@@ -64,6 +137,20 @@ func (eo *Error) Inspect() string {
 	return "ERROR: " + eo.Message
 }
 
+// CaughtError is an error caught by `try`/`catch` and bound to the second
+// catch parameter, as in `catch (message, err)`. Unlike an Error it is an
+// ordinary value, so the handler can pass it around and inspect it with
+// errorMessage and friends.
+type CaughtError struct {
+	Error *Error
+}
+
+func (ce *CaughtError) Type() ObjectType { return CAUGHT_ERROR_OBJ }
+
+func (ce *CaughtError) Inspect() string {
+	return ce.Error.Message
+}
+
 type Function struct {
 	Parameters []*ast.Identifier
 	Body       *ast.BlockStatement
@@ -91,6 +178,37 @@ func (f *Function) Inspect() string {
 	return out.String()
 }
 
+// Quote holds an unevaluated piece of code, as returned by `quote` and
+// passed to macros for their arguments.
+type Quote struct {
+	Node ast.Node
+}
+
+func (q *Quote) Type() ObjectType { return QUOTE_OBJ }
+
+func (q *Quote) Inspect() string {
+	return "QUOTE(" + q.Node.String() + ")"
+}
+
+// Macro is a macro bound during macro expansion. Unlike a function it is
+// called with its arguments unevaluated and returns the code to run in
+// place of the call.
+type Macro struct {
+	Parameters []*ast.Identifier
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+func (m *Macro) Type() ObjectType { return MACRO_OBJ }
+
+func (m *Macro) Inspect() string {
+	params := []string{}
+	for _, p := range m.Parameters {
+		params = append(params, p.String())
+	}
+	return "macro(" + strings.Join(params, ", ") + ") {\n" + m.Body.String() + "\n}"
+}
+
 type String struct {
 	Value string
 }
@@ -107,6 +225,18 @@ type (
 	BuiltinFunction func(args ...Object) Object
 	Builtin         struct {
 		Fn BuiltinFunction
+		// Signature and Description document the builtin for `help`,
+		// e.g. "len(x) -> INTEGER" and "length of a string, array or hash"
+		Signature   string
+		Description string
+		// Arity is the number of arguments the builtin requires. Variadic
+		// ones also take more, optional or any number of them.
+		Arity    int
+		Variadic bool
+		// System marks builtins that reach outside the interpreter, like
+		// reading environment variables. They are only available when the
+		// evaluation allows system access.
+		System bool
 	}
 )
 
@@ -118,27 +248,22 @@ func (bf *Builtin) Inspect() string {
 	return "builtin function"
 }
 
+// Array is an ordered list of objects. A Frozen array cannot be changed.
+// Arrays never grow or shrink in place, so unlike hashes they cannot be
+// sealed.
 type Array struct {
 	Elements []Object
+	Frozen   bool
 }
 
 func (a *Array) Type() ObjectType {
 	return ARRAY_OBJ
 }
 
+// Inspect renders an array that contains itself, directly or through other
+// containers, as [...] where it repeats.
 func (a *Array) Inspect() string {
-	var out bytes.Buffer
-	elements := []string{}
-
-	for _, e := range a.Elements {
-		elements = append(elements, e.Inspect())
-	}
-
-	out.WriteString("[")
-	out.WriteString(strings.Join(elements, ", "))
-	out.WriteString("]")
-
-	return out.String()
+	return InspectLimited(a, 0, 0)
 }
 
 type HashKey struct {
@@ -174,31 +299,96 @@ type HashPair struct {
 	Value Object
 }
 
+// Hash maps hash keys to their pairs. Keys records the insertion order of
+// the pairs added through Set, so enumeration and Inspect are deterministic.
+// Tag names the logical type of the hash, as set by the `tag` builtin.
+// A Frozen hash cannot be changed, a Sealed one only allows replacing the
+// values of its existing keys.
 type Hash struct {
-	Pairs map[HashKey]HashPair
+	Pairs  map[HashKey]HashPair
+	Keys   []HashKey
+	Tag    string
+	Frozen bool
+	Sealed bool
 }
 
-func (ha *Hash) Type() ObjectType { return HASH_OBJ }
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
 
-func (ha *Hash) Inspect() string {
-	var out bytes.Buffer
-	pairs := []string{}
+// Set adds or replaces the pair for key. A replaced pair keeps its position.
+func (ha *Hash) Set(key HashKey, pair HashPair) {
+	if ha.Pairs == nil {
+		ha.Pairs = make(map[HashKey]HashPair)
+	}
+	if _, ok := ha.Pairs[key]; !ok {
+		ha.Keys = append(ha.Keys, key)
+	}
+	ha.Pairs[key] = pair
+}
+
+// OrderedPairs returns the pairs in insertion order. Hashes whose Pairs were
+// filled in directly have no reliable order and are sorted by key instead.
+func (ha *Hash) OrderedPairs() []HashPair {
+	pairs := make([]HashPair, 0, len(ha.Pairs))
+
+	if len(ha.Keys) == len(ha.Pairs) {
+		for _, key := range ha.Keys {
+			pairs = append(pairs, ha.Pairs[key])
+		}
+		return pairs
+	}
 
 	for _, pair := range ha.Pairs {
-		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
+		pairs = append(pairs, pair)
 	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Key.Inspect() < pairs[j].Key.Inspect()
+	})
+	return pairs
+}
 
-	out.WriteString("{")
-	out.WriteString(strings.Join(pairs, ", "))
-	out.WriteString("}")
+func (ha *Hash) Type() ObjectType { return HASH_OBJ }
 
-	return out.String()
+// Inspect renders a hash that contains itself, directly or through other
+// containers, as {...} where it repeats.
+func (ha *Hash) Inspect() string {
+	return InspectLimited(ha, 0, 0)
 }
 
 type Hashable interface {
 	HashKey() HashKey
 }
 
+// Set is an unordered collection of distinct hashable objects, keyed by
+// their HashKey for constant time membership tests.
+type Set struct {
+	Elements map[HashKey]Object
+}
+
+func (s *Set) Type() ObjectType { return SET_OBJ }
+
+func (s *Set) Inspect() string {
+	return InspectLimited(s, 0, 0)
+}
+
+// Bytes is a sequence of raw bytes, kept apart from arrays of integers so
+// binary data stays compact.
+type Bytes struct {
+	Value []byte
+}
+
+func (b *Bytes) Type() ObjectType { return BYTES_OBJ }
+
+func (b *Bytes) Inspect() string {
+	elements := make([]string, len(b.Value))
+	for i, v := range b.Value {
+		elements[i] = strconv.Itoa(int(v))
+	}
+
+	return "bytes(" + strings.Join(elements, ", ") + ")"
+}
+
 type Null struct{}
 
 func (null *Null) Type() ObjectType {
@@ -211,6 +401,7 @@ func (null *Null) Inspect() string {
 
 const (
 	INTEGER_OBJ  = "INTEGER"
+	FLOAT_OBJ    = "FLOAT"
 	BOOLEAN_OBJ  = "BOOLEAN"
 	NULL_OBJ     = "NULL"
 	RETURN_OBJ   = "RETURN"
@@ -220,4 +411,15 @@ const (
 	BUILTIN_OBJ  = "BUILTIN"
 	ARRAY_OBJ    = "ARRAY"
 	HASH_OBJ     = "HASH"
+	SET_OBJ      = "SET"
+	BYTES_OBJ    = "BYTES"
+	EXIT_OBJ     = "EXIT"
+	STRUCT_OBJ   = "STRUCT"
+	INSTANCE_OBJ = "INSTANCE"
+	BREAK_OBJ    = "BREAK"
+	CONTINUE_OBJ = "CONTINUE"
+
+	CAUGHT_ERROR_OBJ = "CAUGHT_ERROR"
+	QUOTE_OBJ        = "QUOTE"
+	MACRO_OBJ        = "MACRO"
 )
@@ -1,6 +1,9 @@
 package object
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestStringHashKey(t *testing.T) {
 	hello1 := &String{Value: "Hello"}
@@ -20,3 +23,219 @@ func TestStringHashKey(t *testing.T) {
 		t.Fatalf("Same hashkey but different value")
 	}
 }
+
+func TestEnvironmentSetGlobal(t *testing.T) {
+	global := NewEnvironment()
+	inner := NewEnclosedEnvironment(NewEnclosedEnvironment(global))
+
+	if inner.Global() != global {
+		t.Fatalf("inner.Global() is not the outermost environment")
+	}
+
+	inner.SetGlobal("x", &Integer{Value: 1})
+
+	if _, ok := global.pool["x"]; !ok {
+		t.Fatalf("x was not bound in the global environment")
+	}
+	if _, ok := inner.pool["x"]; ok {
+		t.Fatalf("x was bound in the inner environment")
+	}
+}
+
+func TestEnvironmentAssign(t *testing.T) {
+	global := NewEnvironment()
+	global.Set("x", &Integer{Value: 1})
+	inner := NewEnclosedEnvironment(global)
+
+	if _, ok := inner.Assign("x", &Integer{Value: 2}); !ok {
+		t.Fatalf("assigning a bound name failed")
+	}
+	if x, _ := global.Get("x"); x.Inspect() != "2" {
+		t.Errorf("x not updated in the global environment, got: %s", x.Inspect())
+	}
+	if _, ok := inner.pool["x"]; ok {
+		t.Errorf("x was bound in the inner environment")
+	}
+	if _, ok := inner.Assign("y", &Integer{Value: 1}); ok {
+		t.Errorf("assigning an unbound name succeeded")
+	}
+	if _, ok := global.Get("y"); ok {
+		t.Errorf("assigning an unbound name created a binding")
+	}
+}
+
+func TestEnvironmentKeys(t *testing.T) {
+	global := NewEnvironment()
+	global.Set("b", &Integer{Value: 1})
+	global.Set("a", &Integer{Value: 2})
+	inner := NewEnclosedEnvironment(global)
+	inner.Set("c", &Integer{Value: 3})
+	inner.Set("a", &Integer{Value: 4})
+
+	keys := inner.Keys()
+	if strings.Join(keys, ",") != "a,b,c" {
+		t.Errorf("wrong keys for inner environment: %v", keys)
+	}
+	if keys := global.Keys(); strings.Join(keys, ",") != "a,b" {
+		t.Errorf("wrong keys for global environment: %v", keys)
+	}
+}
+
+func TestHashOrderedPairs(t *testing.T) {
+	hash := NewHash()
+	for _, key := range []string{"c", "a", "b", "a"} {
+		str := &String{Value: key}
+		hash.Set(str.HashKey(), HashPair{Key: str, Value: str})
+	}
+
+	pairs := hash.OrderedPairs()
+	expected := []string{"c", "a", "b"}
+	if len(pairs) != len(expected) {
+		t.Fatalf("wrong number of pairs, expected: %d, got: %d", len(expected), len(pairs))
+	}
+	for i, key := range expected {
+		if pairs[i].Key.Inspect() != key {
+			t.Errorf("pairs[%d] wrong, expected: %s, got: %s", i, key, pairs[i].Key.Inspect())
+		}
+	}
+}
+
+func TestInspectLimited(t *testing.T) {
+	ints := func(values ...int64) *Array {
+		arr := &Array{}
+		for _, v := range values {
+			arr.Elements = append(arr.Elements, &Integer{Value: v})
+		}
+		return arr
+	}
+	nested := &Array{Elements: []Object{&Integer{Value: 1}, &Array{Elements: []Object{ints(2, 3)}}}}
+	hash := NewHash()
+	for i := int64(1); i <= 3; i++ {
+		key := &Integer{Value: i}
+		hash.Set(key.HashKey(), HashPair{Key: key, Value: ints(i)})
+	}
+
+	tests := []struct {
+		obj              Object
+		maxLen, maxDepth int
+		expected         string
+	}{
+		{ints(1, 2, 3, 4), 0, 0, "[1, 2, 3, 4]"},
+		{ints(1, 2, 3, 4), 2, 0, "[1, 2, ...]"},
+		{ints(1, 2), 2, 0, "[1, 2]"},
+		{nested, 0, 0, "[1, [[2, 3]]]"},
+		{nested, 0, 2, "[1, [[...]]]"},
+		{nested, 0, 1, "[1, [...]]"},
+		{hash, 2, 0, "{1: [1], 2: [2], ...}"},
+		{hash, 0, 1, "{1: [...], 2: [...], 3: [...]}"},
+		{&Set{Elements: map[HashKey]Object{(&Integer{Value: 1}).HashKey(): &Integer{Value: 1}, (&Integer{Value: 2}).HashKey(): &Integer{Value: 2}}}, 1, 0, "set(1, ...)"},
+		{&Bytes{Value: []byte{1, 2, 3}}, 2, 0, "bytes(1, 2, ...)"},
+		{&String{Value: "long string"}, 2, 1, "long string"},
+	}
+
+	for _, tt := range tests {
+		if got := InspectLimited(tt.obj, tt.maxLen, tt.maxDepth); got != tt.expected {
+			t.Errorf("InspectLimited(%s, %d, %d) wrong. expected=%q, got=%q",
+				tt.obj.Inspect(), tt.maxLen, tt.maxDepth, tt.expected, got)
+		}
+	}
+}
+
+func TestInspectCycles(t *testing.T) {
+	one := &Integer{Value: 1}
+	key := &String{Value: "self"}
+
+	arr := &Array{Elements: []Object{one}}
+	arr.Elements = append(arr.Elements, arr)
+
+	hash := NewHash()
+	hash.Set(key.HashKey(), HashPair{Key: key, Value: hash})
+
+	// a hash and an array holding each other
+	outer := NewHash()
+	inner := &Array{Elements: []Object{outer}}
+	outer.Set(key.HashKey(), HashPair{Key: key, Value: inner})
+
+	// the same array twice is shared, not cyclic
+	shared := &Array{Elements: []Object{one}}
+	twice := &Array{Elements: []Object{shared, shared}}
+
+	point := &Struct{Name: "Point", Fields: []string{"x"}}
+	instance := &Instance{Struct: point, Fields: map[string]Object{}}
+	instance.Fields["x"] = &Array{Elements: []Object{instance}}
+
+	tests := []struct {
+		obj      Object
+		expected string
+	}{
+		{arr, "[1, [...]]"},
+		{hash, `{"self": {...}}`},
+		{outer, `{"self": [{...}]}`},
+		{inner, `[{"self": [...]}]`},
+		{twice, "[[1], [1]]"},
+		{instance, "Point(x: [Point(...)])"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.obj.Inspect(); got != tt.expected {
+			t.Errorf("Inspect wrong. expected=%q, got=%q", tt.expected, got)
+		}
+		if got := InspectLimited(tt.obj, 0, 0); got != tt.expected {
+			t.Errorf("InspectLimited wrong. expected=%q, got=%q", tt.expected, got)
+		}
+	}
+}
+
+func TestInspectQuotesNestedStrings(t *testing.T) {
+	str := func(s string) *String { return &String{Value: s} }
+	hash := NewHash()
+	hash.Set(str("key").HashKey(), HashPair{Key: str("key"), Value: str("say \"hi\"\n")})
+	point := &Struct{Name: "Point", Fields: []string{"label"}}
+
+	tests := []struct {
+		obj      Object
+		expected string
+	}{
+		{str("a, b"), "a, b"},
+		{&Array{Elements: []Object{str("a"), str("b")}}, `["a", "b"]`},
+		{&Array{Elements: []Object{str("a, b")}}, `["a, b"]`},
+		{&Array{Elements: []Object{str("tab\there"), str("")}}, `["tab\there", ""]`},
+		{&Array{Elements: []Object{str("héllo")}}, `["héllo"]`},
+		{hash, `{"key": "say \"hi\"\n"}`},
+		{&Instance{Struct: point, Fields: map[string]Object{"label": str("origin")}}, `Point(label: "origin")`},
+		{&Set{Elements: map[HashKey]Object{str("x").HashKey(): str("x")}}, `set("x")`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.obj.Inspect(); got != tt.expected {
+			t.Errorf("Inspect wrong. expected=%q, got=%q", tt.expected, got)
+		}
+	}
+}
+
+func TestEnvironmentSnapshot(t *testing.T) {
+	env := NewEnvironment()
+	arr := &Array{Elements: []Object{&Integer{Value: 1}}}
+	env.Set("a", &Integer{Value: 1})
+	env.Set("arr", arr)
+
+	snapshot := env.Snapshot()
+	env.Set("a", &Integer{Value: 2})
+	env.Set("b", &Integer{Value: 3})
+	arr.Elements[0] = &Integer{Value: 9}
+
+	for i := 0; i < 2; i++ {
+		env.Restore(snapshot)
+		if a, ok := env.Get("a"); !ok || a.Inspect() != "1" {
+			t.Errorf("a not restored, got: %v", a)
+		}
+		if _, ok := env.Get("b"); ok {
+			t.Errorf("b still bound after Restore")
+		}
+		// the snapshot is shallow, in place changes are kept
+		if got, _ := env.Get("arr"); got.Inspect() != "[9]" {
+			t.Errorf("arr wrong, expected: [9], got: %s", got.Inspect())
+		}
+		env.Set("a", &Integer{Value: 4})
+	}
+}
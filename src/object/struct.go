@@ -0,0 +1,42 @@
+package object
+
+// Struct is a user defined type declared with `struct`. Calling it builds an
+// Instance from the field values in declaration order.
+type Struct struct {
+	Name    string
+	Fields  []string
+	Methods map[string]*Function
+}
+
+func (s *Struct) Type() ObjectType { return STRUCT_OBJ }
+
+func (s *Struct) Inspect() string {
+	return "struct " + s.Name
+}
+
+// Instance is a value of a Struct.
+type Instance struct {
+	Struct *Struct
+	Fields map[string]Object
+}
+
+func (i *Instance) Type() ObjectType { return INSTANCE_OBJ }
+
+// Inspect renders an instance that contains itself through a container as
+// Name(...) where it repeats.
+func (i *Instance) Inspect() string {
+	return InspectLimited(i, 0, 0)
+}
+
+// BoundMethod is a method read from an instance, or a function read from a
+// hash with `.`. Calling it binds Receiver to self in the method's scope.
+type BoundMethod struct {
+	Receiver Object
+	Method   *Function
+}
+
+func (bm *BoundMethod) Type() ObjectType { return FUNCTION_OBJ }
+
+func (bm *BoundMethod) Inspect() string {
+	return bm.Method.Inspect()
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 
 	"monkey/src/ast"
@@ -17,19 +18,28 @@ type (
 type Parser struct {
 	l *lexer.Lexer
 
-	errors []string
+	errors   []parseError
+	warnings []parseError
 
 	curToken  token.Token
 	peekToken token.Token
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
+
+	// loopDepth counts the loops around the current token within the
+	// innermost function, break and continue are only valid inside one
+	loopDepth int
+
+	// inFunction is set while parsing a function body, defer is only valid
+	// inside one
+	inFunction bool
 }
 
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{
 		l:      l,
-		errors: []string{},
+		errors: []parseError{},
 	}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
@@ -38,24 +48,38 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.MACRO, p.parseMacroLiteral)
 	p.registerPrefix(token.LPAREN, p.parseGroupExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
+	p.registerPrefix(token.DO, p.parseDoWhileExpression)
+	p.registerPrefix(token.FOR, p.parseForInExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.NULL, p.parseNullLiteral)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.ILLEGAL, p.parseIllegal)
+	p.registerPrefix(token.ELLIPSIS, p.parseSpreadExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.PERCENT, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.IN, p.parseInfixExpression)
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.INCREMENT, p.parsePostfixExpression)
+	p.registerInfix(token.DECREMENT, p.parsePostfixExpression)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
 
 	p.nextToken()
 	p.nextToken()
@@ -72,9 +96,12 @@ func (p *Parser) ParseProgram() *ast.Program {
 	program := &ast.Program{}
 	program.Statements = []ast.Statement{}
 
+	var reachable reachability
 	for p.curToken.Type != token.EOF {
+		tok := p.curToken
 		stm := p.parseStatement()
 		if stm != nil {
+			reachable.check(p, tok, stm)
 			program.Statements = append(program.Statements, stm)
 		}
 		p.nextToken()
@@ -82,6 +109,31 @@ func (p *Parser) ParseProgram() *ast.Program {
 	return program
 }
 
+// reachability tracks whether the statements of a block can still run, to
+// warn once about the first one following a return, break or continue.
+type reachability struct {
+	exit   string
+	warned bool
+}
+
+// check is called for every statement stm of the block, tok is its first
+// token.
+func (r *reachability) check(p *Parser, tok token.Token, stm ast.Statement) {
+	if r.exit != "" {
+		if !r.warned {
+			p.warnings = append(p.warnings, parseError{tok: tok, msg: fmt.Sprintf(
+				"unreachable code after %s (line %d, column %d)", r.exit, tok.Line, tok.Column)})
+			r.warned = true
+		}
+		return
+	}
+
+	switch stm.(type) {
+	case *ast.ReturnStatement, *ast.BreakStatement, *ast.ContinueStatement:
+		r.exit = stm.TokenLiteral()
+	}
+}
+
 func (p *Parser) registerPrefix(tokType token.TokenType, fn prefixParseFn) {
 	p.prefixParseFns[tokType] = fn
 }
@@ -93,15 +145,31 @@ func (p *Parser) registerInfix(tokType token.TokenType, fn infixParseFn) {
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
 	case token.LET:
+		if p.peekTokenIs(token.LBRACKET) {
+			return p.parseDestructuringStatement()
+		}
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.GLOBAL:
+		return p.parseGlobalStatement()
+	case token.STRUCT:
+		return p.parseStructStatement()
+	case token.BREAK, token.CONTINUE:
+		return p.parseLoopControlStatement()
+	case token.DEFER:
+		return p.parseDeferStatement()
+	case token.IDENT:
+		if p.peekTokenIs(token.COMMA) {
+			return p.parseParallelAssignStatement()
+		}
+		return p.parseExpressionStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
 }
 
-func (p *Parser) parseLetStatement() *ast.LetStatement {
+func (p *Parser) parseLetStatement() ast.Statement {
 	stm := &ast.LetStatement{
 		Token: p.curToken,
 	}
@@ -115,6 +183,194 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		Value: p.curToken.Literal,
 	}
 
+	if p.peekTokenIs(token.COMMA) {
+		parallel := &ast.ParallelLetStatement{Token: stm.Token}
+		parallel.Names, _, parallel.Values = p.parseParallelBindings()
+		if parallel.Values == nil {
+			return nil
+		}
+		return parallel
+	}
+
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+	p.nextToken()
+	stm.Value = p.parseExpression(LOWEST)
+
+	for p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stm
+}
+
+func (p *Parser) parseDestructuringStatement() *ast.DestructuringStatement {
+	stm := &ast.DestructuringStatement{
+		Token: p.curToken,
+	}
+	p.nextToken()
+
+	for {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stm.Names = append(stm.Names, &ast.Identifier{
+			Token: p.curToken,
+			Value: p.curToken.Literal,
+		})
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+	p.nextToken()
+	stm.Value = p.parseExpression(LOWEST)
+
+	for p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stm
+}
+
+func (p *Parser) parseParallelAssignStatement() ast.Statement {
+	stm := &ast.ParallelAssignStatement{}
+	stm.Names, stm.Token, stm.Values = p.parseParallelBindings()
+	if stm.Values == nil {
+		return nil
+	}
+	return stm
+}
+
+// parseParallelBindings parses `a, b = x, y` starting at the first name. All
+// values are parsed before any is bound, and there must be one per name. The
+// values are nil if the bindings could not be parsed.
+func (p *Parser) parseParallelBindings() ([]*ast.Identifier, token.Token, []ast.Expression) {
+	names := []*ast.Identifier{{Token: p.curToken, Value: p.curToken.Literal}}
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil, token.Token{}, nil
+		}
+		names = append(names, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+	}
+
+	if !p.expectPeek(token.ASSIGN) {
+		return nil, token.Token{}, nil
+	}
+	assign := p.curToken
+
+	values := []ast.Expression{}
+	for {
+		p.nextToken()
+		values = append(values, p.parseExpression(LOWEST))
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	for p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	if len(values) != len(names) {
+		p.addError(assign, fmt.Sprintf("assignment mismatch: %d names but %d values", len(names), len(values)))
+		return nil, token.Token{}, nil
+	}
+
+	return names, assign, values
+}
+
+// parseStructStatement parses the fields and methods of a struct. Members may
+// be separated by commas or semicolons, which are optional.
+func (p *Parser) parseStructStatement() *ast.StructStatement {
+	stm := &ast.StructStatement{
+		Token: p.curToken,
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stm.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBRACE) {
+		switch p.curToken.Type {
+		case token.IDENT:
+			stm.Fields = append(stm.Fields, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+		case token.FUNCTION:
+			method := p.parseStructMethod()
+			if method == nil {
+				return nil
+			}
+			stm.Methods = append(stm.Methods, method)
+		case token.COMMA, token.SEMICOLON:
+		default:
+			p.addError(p.curToken, fmt.Sprintf("unexpected %s in struct %s", p.curToken.Type, stm.Name.Value))
+			return nil
+		}
+		p.nextToken()
+	}
+
+	for p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stm
+}
+
+func (p *Parser) parseStructMethod() *ast.StructMethod {
+	lit := &ast.FunctionLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	method := &ast.StructMethod{
+		Name:     &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+		Function: lit,
+	}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	lit.Parameters = p.parseFunctionParameters()
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	lit.Body = p.parseFunctionBody()
+
+	return method
+}
+
+func (p *Parser) parseGlobalStatement() *ast.GlobalStatement {
+	stm := &ast.GlobalStatement{
+		Token: p.curToken,
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stm.Name = &ast.Identifier{
+		Token: p.curToken,
+		Value: p.curToken.Literal,
+	}
+
 	if !p.expectPeek(token.ASSIGN) {
 		return nil
 	}
@@ -136,7 +392,39 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 
 	stm.ReturnValue = p.parseExpression(LOWEST)
 
-	for !p.curTokenIs(token.SEMICOLON) {
+	for p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stm
+}
+
+func (p *Parser) parseLoopControlStatement() ast.Statement {
+	tok := p.curToken
+	if p.loopDepth == 0 {
+		p.addError(tok, fmt.Sprintf("%s outside loop", tok.Literal))
+	}
+
+	for p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	if tok.Type == token.BREAK {
+		return &ast.BreakStatement{Token: tok}
+	}
+	return &ast.ContinueStatement{Token: tok}
+}
+
+func (p *Parser) parseDeferStatement() *ast.DeferStatement {
+	stm := &ast.DeferStatement{Token: p.curToken}
+	if !p.inFunction {
+		p.addError(stm.Token, "defer outside function")
+	}
+
+	p.nextToken()
+	stm.Expression = p.parseExpression(LOWEST)
+
+	for p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
@@ -192,6 +480,15 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseSpreadExpression() ast.Expression {
+	expression := &ast.SpreadExpression{Token: p.curToken}
+
+	p.nextToken()
+	expression.Value = p.parseExpression(PREFIX)
+
+	return expression
+}
+
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{
 		Token: p.curToken,
@@ -214,6 +511,10 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
+		// allow a trailing comma before the closing token
+		if p.peekTokenIs(end) {
+			break
+		}
 		p.nextToken()
 		list = append(list, p.parseExpression(LOWEST))
 	}
@@ -237,9 +538,27 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return exp
 }
 
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Object: left}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	return exp
+}
+
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
 	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	p.addError(p.curToken, msg)
+}
+
+func (p *Parser) parseIllegal() ast.Expression {
+	msg := fmt.Sprintf("illegal token: %s (line %d, column %d)",
+		p.curToken.Literal, p.curToken.Line, p.curToken.Column)
+	p.addError(p.curToken, msg)
+	return nil
 }
 
 func (p *Parser) parseIntegerLiteral() ast.Expression {
@@ -248,7 +567,21 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
 	if err != nil {
 		msg := fmt.Sprintf("could not parse %s as an integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.addError(p.curToken, msg)
+		return nil
+	}
+
+	lit.Value = value
+	return lit
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.curToken}
+
+	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	if err != nil {
+		msg := fmt.Sprintf("could not parse %s as a float", p.curToken.Literal)
+		p.addError(p.curToken, msg)
 		return nil
 	}
 
@@ -270,14 +603,125 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+// parseIdentifier also starts `repeat { ... } until (...)` loops. repeat is
+// not a keyword, so the builtin of the same name keeps working.
 func (p *Parser) parseIdentifier() ast.Expression {
+	if p.curToken.Literal == "repeat" && p.peekTokenIs(token.LBRACE) {
+		return p.parseRepeatExpression()
+	}
 	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+func (p *Parser) parseRepeatExpression() ast.Expression {
+	expression := &ast.RepeatExpression{Token: p.curToken}
+
+	body, condition := p.parsePostConditionLoop(token.UNTIL)
+	if body == nil || condition == nil {
+		return nil
+	}
+	expression.Body, expression.Condition = body, condition
+
+	return expression
+}
+
+func (p *Parser) parseDoWhileExpression() ast.Expression {
+	expression := &ast.DoWhileExpression{Token: p.curToken}
+
+	body, condition := p.parsePostConditionLoop(token.WHILE)
+	if body == nil || condition == nil {
+		return nil
+	}
+	expression.Body, expression.Condition = body, condition
+
+	return expression
+}
+
+func (p *Parser) parseForInExpression() ast.Expression {
+	expression := &ast.ForInExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	expression.Variable = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		expression.Key = expression.Variable
+		expression.Variable = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	// the iterable is parsed on its own, `x in arr` would otherwise be read
+	// as a membership test
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+	p.nextToken()
+	expression.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	expression.Body = p.parseLoopBody()
+
+	return expression
+}
+
+// parsePostConditionLoop parses `{ ... } keyword (condition)` following the
+// current token, it returns nils on errors.
+func (p *Parser) parsePostConditionLoop(keyword token.TokenType) (*ast.BlockStatement, ast.Expression) {
+	if !p.expectPeek(token.LBRACE) {
+		return nil, nil
+	}
+	body := p.parseLoopBody()
+
+	if !p.expectPeek(keyword) {
+		return nil, nil
+	}
+	if !p.expectPeek(token.LPAREN) {
+		return nil, nil
+	}
+	p.nextToken()
+	condition := p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil, nil
+	}
+
+	return body, condition
+}
+
+// parseLoopBody parses the block at the current token as the body of a loop.
+func (p *Parser) parseLoopBody() *ast.BlockStatement {
+	p.loopDepth++
+	defer func() { p.loopDepth-- }()
+	return p.parseBlockStatement()
+}
+
+// parseFunctionBody parses the block at the current token as the body of a
+// function. Loops around the function do not reach into it.
+func (p *Parser) parseFunctionBody() *ast.BlockStatement {
+	depth, inFunction := p.loopDepth, p.inFunction
+	p.loopDepth, p.inFunction = 0, true
+	defer func() { p.loopDepth, p.inFunction = depth, inFunction }()
+	return p.parseBlockStatement()
+}
+
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
 }
 
+func (p *Parser) parseNullLiteral() ast.Expression {
+	return &ast.NullLiteral{Token: p.curToken}
+}
+
 func (p *Parser) parseGroupExpression() ast.Expression {
 	p.nextToken()
 
@@ -289,6 +733,42 @@ func (p *Parser) parseGroupExpression() ast.Expression {
 	return exp
 }
 
+// parseAssignExpression parses the value with a lower precedence than its own,
+// so `a = b = 1` assigns right to left. Index expressions on the left, also
+// chained ones like `a[0][1]`, become an IndexAssignExpression.
+func (p *Parser) parseAssignExpression(left ast.Expression) ast.Expression {
+	if target, ok := left.(*ast.IndexExpression); ok {
+		expression := &ast.IndexAssignExpression{Token: p.curToken, Target: target}
+		p.nextToken()
+		expression.Value = p.parseExpression(ASSIGN - 1)
+		return expression
+	}
+
+	expression := &ast.AssignExpression{Token: p.curToken}
+
+	name, ok := left.(*ast.Identifier)
+	if !ok {
+		p.addError(p.curToken, fmt.Sprintf("cannot assign to %s", left.String()))
+		return nil
+	}
+	expression.Name = name
+
+	p.nextToken()
+	expression.Value = p.parseExpression(ASSIGN - 1)
+
+	return expression
+}
+
+func (p *Parser) parsePostfixExpression(left ast.Expression) ast.Expression {
+	name, ok := left.(*ast.Identifier)
+	if !ok {
+		p.addError(p.curToken, fmt.Sprintf("cannot apply %s to %s", p.curToken.Literal, left.String()))
+		return nil
+	}
+
+	return &ast.PostfixExpression{Token: p.curToken, Name: name, Operator: p.curToken.Literal}
+}
+
 func (p *Parser) parseIfExpression() ast.Expression {
 	expression := &ast.IfExpression{
 		Token: p.curToken,
@@ -311,14 +791,89 @@ func (p *Parser) parseIfExpression() ast.Expression {
 
 	expression.Consequence = p.parseBlockStatement()
 
-	if p.peekTokenIs(token.ELSE) {
+	for p.peekTokenIs(token.ELSE) {
 		p.nextToken()
 
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			branch := p.parseElseIfBranch()
+			if branch == nil {
+				return nil
+			}
+			expression.ElseIfs = append(expression.ElseIfs, branch)
+			continue
+		}
+
 		if !p.expectPeek(token.LBRACE) {
 			return nil
 		}
 		expression.Alternative = p.parseBlockStatement()
+		break
+	}
+
+	return expression
+}
+
+func (p *Parser) parseElseIfBranch() *ast.ElseIfBranch {
+	branch := &ast.ElseIfBranch{
+		Token: p.curToken,
+	}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	branch.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	branch.Consequence = p.parseBlockStatement()
+	return branch
+}
+
+func (p *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{
+		Token: p.curToken,
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	expression.Block = p.parseBlockStatement()
+
+	if !p.expectPeek(token.CATCH) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.LPAREN) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		expression.Param = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			expression.ErrorParam = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		}
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
 	}
+	expression.Handler = p.parseBlockStatement()
 
 	return expression
 }
@@ -332,10 +887,13 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 
 	p.nextToken()
 
+	var reachable reachability
 	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
+		tok := p.curToken
 		stm := p.parseStatement()
 
 		if stm != nil {
+			reachable.check(p, tok, stm)
 			block.Statements = append(block.Statements, stm)
 		}
 		p.nextToken()
@@ -360,6 +918,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		value := p.parseExpression(LOWEST)
 
 		hash.Pairs[key] = value
+		hash.Keys = append(hash.Keys, key)
 
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
@@ -389,7 +948,25 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	lit.Body = p.parseBlockStatement()
+	lit.Body = p.parseFunctionBody()
+
+	return lit
+}
+
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	lit := &ast.MacroLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	lit.Parameters = p.parseFunctionParameters()
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	lit.Body = p.parseFunctionBody()
 
 	return lit
 }
@@ -408,6 +985,9 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
+		if p.peekTokenIs(token.RPAREN) {
+			break
+		}
 		p.nextToken()
 		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 		identifiers = append(identifiers, ident)
@@ -422,7 +1002,7 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
-	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	exp.Arguments = p.parseCallArguments()
 	return exp
 }
 
@@ -433,12 +1013,23 @@ func (p *Parser) parseCallArguments() []ast.Expression {
 		return args
 	}
 	p.nextToken()
-	args = append(args, p.parseExpression(LOWEST))
+	args = append(args, p.parseCallArgument())
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
+		if p.peekTokenIs(token.RPAREN) {
+			break
+		}
 		p.nextToken()
-		args = append(args, p.parseExpression(LOWEST))
+
+		start := p.curToken
+		arg := p.parseCallArgument()
+		_, isKeyword := arg.(*ast.KeywordArgument)
+		_, lastIsKeyword := args[len(args)-1].(*ast.KeywordArgument)
+		if lastIsKeyword && !isKeyword {
+			p.addError(start, "positional argument follows keyword argument")
+		}
+		args = append(args, arg)
 	}
 
 	if !p.expectPeek(token.RPAREN) {
@@ -448,6 +1039,24 @@ func (p *Parser) parseCallArguments() []ast.Expression {
 	return args
 }
 
+// parseCallArgument parses either a positional argument or a keyword
+// argument of the form `name = value`.
+func (p *Parser) parseCallArgument() ast.Expression {
+	if !p.curTokenIs(token.IDENT) || !p.peekTokenIs(token.ASSIGN) {
+		return p.parseExpression(LOWEST)
+	}
+
+	arg := &ast.KeywordArgument{
+		Token: p.curToken,
+		Name:  &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+	}
+	p.nextToken()
+	p.nextToken()
+	arg.Value = p.parseExpression(LOWEST)
+
+	return arg
+}
+
 func (p *Parser) peekTokenIs(token token.TokenType) bool {
 	return p.peekToken.Type == token
 }
@@ -466,18 +1075,55 @@ func (p *Parser) expectPeek(token token.TokenType) bool {
 	}
 }
 
+// parseError is a message together with the token it was reported at.
+type parseError struct {
+	tok token.Token
+	msg string
+}
+
+func (p *Parser) addError(tok token.Token, msg string) {
+	p.errors = append(p.errors, parseError{tok: tok, msg: msg})
+}
+
+// Warnings returns messages about code that parses but is likely a mistake,
+// like statements that can never run, in source order.
+func (p *Parser) Warnings() []string {
+	messages := make([]string, len(p.warnings))
+	for i, warning := range p.warnings {
+		messages[i] = warning.msg
+	}
+	return messages
+}
+
+// Errors returns the error messages in source order. Some errors are only
+// detected after the parser moved past the place they refer to, so they are
+// sorted by position rather than kept in the order they were found.
 func (p *Parser) Errors() []string {
-	return p.errors
+	sorted := make([]parseError, len(p.errors))
+	copy(sorted, p.errors)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].tok.Line != sorted[j].tok.Line {
+			return sorted[i].tok.Line < sorted[j].tok.Line
+		}
+		return sorted[i].tok.Column < sorted[j].tok.Column
+	})
+
+	messages := make([]string, len(sorted))
+	for i, err := range sorted {
+		messages[i] = err.msg
+	}
+	return messages
 }
 
 func (p *Parser) peekError(token token.TokenType) {
 	msg := fmt.Sprintf("Expect token to be %s, got %s instead", token, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	p.addError(p.peekToken, msg)
 }
 
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN
 	EQUALS
 	LESSGREATER
 	SUM
@@ -488,16 +1134,22 @@ const (
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.ASTERISK: PRODUCT,
-	token.SLASH:    PRODUCT,
-	token.LPAREN:   CALL,
-	token.LBRACKET: INDEX,
+	token.ASSIGN:    ASSIGN,
+	token.EQ:        EQUALS,
+	token.NOT_EQ:    EQUALS,
+	token.LT:        LESSGREATER,
+	token.GT:        LESSGREATER,
+	token.IN:        LESSGREATER,
+	token.PLUS:      SUM,
+	token.MINUS:     SUM,
+	token.ASTERISK:  PRODUCT,
+	token.SLASH:     PRODUCT,
+	token.PERCENT:   PRODUCT,
+	token.LPAREN:    CALL,
+	token.LBRACKET:  INDEX,
+	token.DOT:       INDEX,
+	token.INCREMENT: INDEX,
+	token.DECREMENT: INDEX,
 }
 
 func (p *Parser) peekPredence() int {
@@ -68,6 +68,27 @@ func TestReturnStatement(t *testing.T) {
 	}
 }
 
+func TestReturnStatementWithoutSemicolon(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"return 5", "return 5;"},
+		{"fn() { return x }", "fn() return x;"},
+		{"if (a) { return 1 } else { return 2 }", "ifa return 1;elsereturn 2;"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("wrong program, expected: %q, got: %q", tt.expected, program.String())
+		}
+	}
+}
+
 func testLetStatement(t *testing.T, statement ast.Statement, name string) bool {
 	if statement.TokenLiteral() != "let" {
 		t.Errorf("Let token literal not `let`, got %s", statement.TokenLiteral())
@@ -366,6 +387,14 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"a + b % c * d",
+			"(a + ((b % c) * d))",
+		},
+		{
+			"a + 1 in b == true",
+			"(((a + 1) in b) == true)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -598,6 +627,51 @@ func TestIfExpression(t *testing.T) {
 	}
 }
 
+func TestElseIfChain(t *testing.T) {
+	input := `if (a) { 1 } else if (b) { 2 } else if (c) { 3 } else { 4 }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserError(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression. got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, exp.Condition, "a") {
+		return
+	}
+	if len(exp.ElseIfs) != 2 {
+		t.Fatalf("exp.ElseIfs does not contain 2 branches. got=%d", len(exp.ElseIfs))
+	}
+	for i, name := range []string{"b", "c"} {
+		if !testIdentifier(t, exp.ElseIfs[i].Condition, name) {
+			return
+		}
+		consequence := exp.ElseIfs[i].Consequence.Statements[0].(*ast.ExpressionStatement)
+		testIntegerLiteral(t, consequence.Expression, int64(i+2))
+	}
+	if exp.Alternative == nil || len(exp.Alternative.Statements) != 1 {
+		t.Fatalf("exp.Alternative is not a block with 1 statement. got=%v", exp.Alternative)
+	}
+
+	expected := "ifa 1else ifb 2else ifc 3else4"
+	if exp.String() != expected {
+		t.Errorf("exp.String() wrong. expected=%q, got=%q", expected, exp.String())
+	}
+
+	for _, input := range []string{"if (a) { 1 } else if { 2 }", "if (a) { 1 } else if (b) 2"} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
 func TestIfElseExpression(t *testing.T) {
 	input := `if (x < y) { x } else { y }`
 
@@ -955,3 +1029,751 @@ func TestParsingLiteralWithTypes(t *testing.T) {
 		testFunc(val)
 	}
 }
+
+func TestTrailingCommas(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3,]", "[1, 2, 3]"},
+		{"[\n  1,\n  2,\n]", "[1, 2]"},
+		{"add(1, 2,)", "add(1, 2)"},
+		{"fn(x, y,) { x }", "fn(x, y) x"},
+		{`{"a": 1,}`, "{a:1}"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement, got: %d", len(program.Statements))
+		}
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("Expected: %q, got: %q", tt.expected, actual)
+		}
+	}
+}
+
+func TestTrailingCommaElementCount(t *testing.T) {
+	input := `[1, 2,]; f(a, b,); fn(x, y,) {}; {"a": 1, "b": 2,}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserError(t, p)
+
+	if len(program.Statements) != 4 {
+		t.Fatalf("program.Statements does not contain 4 statements, got: %d", len(program.Statements))
+	}
+
+	array := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.ArrayLiteral)
+	if len(array.Elements) != 2 {
+		t.Errorf("array.Elements length wrong, expected: 2, got: %d", len(array.Elements))
+	}
+
+	call := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	if len(call.Arguments) != 2 {
+		t.Errorf("call.Arguments length wrong, expected: 2, got: %d", len(call.Arguments))
+	}
+
+	function := program.Statements[2].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if len(function.Parameters) != 2 {
+		t.Errorf("function.Parameters length wrong, expected: 2, got: %d", len(function.Parameters))
+	}
+
+	hash := program.Statements[3].(*ast.ExpressionStatement).Expression.(*ast.HashLiteral)
+	if len(hash.Pairs) != 2 {
+		t.Errorf("hash.Pairs length wrong, expected: 2, got: %d", len(hash.Pairs))
+	}
+}
+
+func TestKeywordArguments(t *testing.T) {
+	input := `createUser("a", age = 30, admin = true)`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserError(t, p)
+
+	call := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	if len(call.Arguments) != 3 {
+		t.Fatalf("call.Arguments length wrong, expected: 3, got: %d", len(call.Arguments))
+	}
+
+	if str, ok := call.Arguments[0].(*ast.StringLiteral); !ok || str.Value != "a" {
+		t.Errorf("call.Arguments[0] is not StringLiteral a, got: %T (%+v)", call.Arguments[0], call.Arguments[0])
+	}
+
+	kw, ok := call.Arguments[1].(*ast.KeywordArgument)
+	if !ok {
+		t.Fatalf("call.Arguments[1] is not KeywordArgument, got: %T", call.Arguments[1])
+	}
+	if kw.Name.Value != "age" {
+		t.Errorf("kw.Name wrong, expected: age, got: %s", kw.Name.Value)
+	}
+	testLiteralExpression(t, kw.Value, 30)
+
+	if call.String() != `createUser(a, age = 30, admin = true)` {
+		t.Errorf("call.String() wrong, got: %s", call.String())
+	}
+}
+
+func TestPositionalAfterKeywordArgument(t *testing.T) {
+	l := lexer.New(`f(a = 1, 2)`)
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != "positional argument follows keyword argument" {
+		t.Fatalf("unexpected parser errors: %v", errors)
+	}
+}
+
+func TestGlobalStatement(t *testing.T) {
+	l := lexer.New("global counter = counter + 1;")
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserError(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement, got: %d", len(program.Statements))
+	}
+
+	stm, ok := program.Statements[0].(*ast.GlobalStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not GlobalStatement, got: %T", program.Statements[0])
+	}
+
+	if stm.Name.Value != "counter" {
+		t.Errorf("stm.Name wrong, expected: counter, got: %s", stm.Name.Value)
+	}
+
+	testInfixExpression(t, stm.Value, "counter", "+", 1)
+}
+
+func TestDestructuringStatement(t *testing.T) {
+	l := lexer.New("let [q, r] = divmod(17, 5);")
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserError(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement, got: %d", len(program.Statements))
+	}
+
+	stm, ok := program.Statements[0].(*ast.DestructuringStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not DestructuringStatement, got: %T", program.Statements[0])
+	}
+
+	if len(stm.Names) != 2 || stm.Names[0].Value != "q" || stm.Names[1].Value != "r" {
+		t.Errorf("stm.Names wrong, expected: [q r], got: %v", stm.Names)
+	}
+	if stm.String() != "let [q, r] = divmod(17, 5);" {
+		t.Errorf("stm.String() wrong, got: %q", stm.String())
+	}
+
+	for _, input := range []string{"let [] = x", "let [a, 1] = x", "let [a = x", "let [a] x"} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestParallelAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a, b = 1, 2;", "let a, b = 1, 2;"},
+		{"let a, b, c = f(x), [1, 2], 3 + 4", "let a, b, c = f(x), [1, 2], (3 + 4);"},
+		{"a, b = b, a;", "a, b = b, a;"},
+		{"a, b = b + 1, a", "a, b = (b + 1), a;"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement, got: %d", len(program.Statements))
+		}
+		if program.String() != tt.expected {
+			t.Errorf("program.String() wrong, expected: %q, got: %q", tt.expected, program.String())
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{"let a, b = 1;", "assignment mismatch: 2 names but 1 values"},
+		{"a, b = 1, 2, 3;", "assignment mismatch: 2 names but 3 values"},
+		{"let a, 1 = 1, 2;", "Expect token to be ident, got INT instead"},
+	}
+
+	for _, tt := range errors {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 || p.Errors()[0] != tt.expected {
+			t.Errorf("errors for %q wrong, expected: %q, got: %q", tt.input, tt.expected, p.Errors())
+		}
+	}
+}
+
+func TestStructStatement(t *testing.T) {
+	input := `struct Point {
+	x, y
+	fn norm() { self.x * self.x + self.y * self.y }
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserError(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement, got: %d", len(program.Statements))
+	}
+
+	stm, ok := program.Statements[0].(*ast.StructStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not StructStatement, got: %T", program.Statements[0])
+	}
+
+	if stm.Name.Value != "Point" {
+		t.Errorf("stm.Name wrong, expected: Point, got: %s", stm.Name.Value)
+	}
+	if len(stm.Fields) != 2 || stm.Fields[0].Value != "x" || stm.Fields[1].Value != "y" {
+		t.Errorf("stm.Fields wrong, expected: [x y], got: %v", stm.Fields)
+	}
+	if len(stm.Methods) != 1 || stm.Methods[0].Name.Value != "norm" {
+		t.Fatalf("stm.Methods wrong, got: %v", stm.Methods)
+	}
+
+	expected := "(((self.x) * (self.x)) + ((self.y) * (self.y)))"
+	if body := stm.Methods[0].Function.Body.String(); body != expected {
+		t.Errorf("method body wrong, expected: %q, got: %q", expected, body)
+	}
+
+	p = New(lexer.New("struct P { x, y }; let p = P(1, 2)"))
+	program = p.ParseProgram()
+	checkParserError(t, p)
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2 statements, got: %d", len(program.Statements))
+	}
+	if _, ok := program.Statements[1].(*ast.LetStatement); !ok {
+		t.Errorf("program.Statements[1] is not LetStatement, got: %T", program.Statements[1])
+	}
+
+	for _, input := range []string{"struct { x }", "struct P { 1 }", "struct P { x", "struct P { fn () {} }"} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestMemberExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"p.x", "(p.x)"},
+		{"p.x.y", "((p.x).y)"},
+		{"p.move(1)", "(p.move)(1)"},
+		{"-p.x * 2", "((-(p.x)) * 2)"},
+		{"a[0].x", "((a[0]).x)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("Expected: %q, got: %q", tt.expected, actual)
+		}
+	}
+}
+
+func TestRepeatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"repeat { x = x + 1 } until (x > 3)", "repeat x = (x + 1) until (x > 3)"},
+		{"repeat { if (x) { break; } continue; } until (true)", "repeat ifx break;continue; until true"},
+		{"repeat(\"ab\", 2)", "repeat(ab, 2)"},
+		{"fn() { repeat { fn() { 1 } } until (y) }", "fn() repeat fn() 1 until y"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"break;", "break outside loop"},
+		{"if (x) { continue }", "continue outside loop"},
+		{"repeat { fn() { break } } until (x)", "break outside loop"},
+		{"repeat { 1 }", "Expect token to be UNTIL, got EOF instead"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("unexpected parser errors for %q, expected: %q, got: %v", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestDoWhileExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"do { i++ } while (i < 3)", "do (i++) while (i < 3)"},
+		{"do { if (x) { break } } while (true)", "do ifx break; while true"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"do 1 while (x)", "Expect token to be {, got INT instead"},
+		{"do { 1 } until (x)", "Expect token to be WHILE, got UNTIL instead"},
+		{"do { 1 } while x", "Expect token to be (, got ident instead"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("unexpected parser errors for %q, expected: %q, got: %v", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestForInExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"for (x in arr) { put(x) }", "for (x in arr) put(x)"},
+		{"for (x in [1, 2]) { if (x in s) { continue } }", "for (x in [1, 2]) if(x in s) continue;"},
+		{"for (k in keys(h)) { break }", "for (k in keys(h)) break;"},
+		{"for (k, v in h) { put(k, v) }", "for (k, v in h) put(k, v)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"for x in arr { 1 }", "Expect token to be (, got ident instead"},
+		{"for (1 in arr) { 1 }", "Expect token to be ident, got INT instead"},
+		{"for (x of arr) { 1 }", "Expect token to be IN, got ident instead"},
+		{"for (k, in h) { 1 }", "Expect token to be ident, got IN instead"},
+		{"for (a, b, c in h) { 1 }", "Expect token to be IN, got , instead"},
+		{"for (x in arr) 1", "Expect token to be {, got INT instead"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("unexpected parser errors for %q, expected: %q, got: %v", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestPostfixExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"i++", "(i++)"},
+		{"i--;", "(i--)"},
+		{"i++ * 2", "((i++) * 2)"},
+		{"-i++", "(-(i++))"},
+		{"a[i++]", "(a[(i++)])"},
+		{"5--3", "(5 - (-3))"},
+		{"--5", "(-(-5))"},
+		{"i - --j", "(i - (-(-j)))"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	p := New(lexer.New("a[0]++"))
+	p.ParseProgram()
+	errors := p.Errors()
+	if len(errors) == 0 || errors[0] != "cannot apply ++ to (a[0])" {
+		t.Errorf("unexpected parser errors: %v", errors)
+	}
+}
+
+func TestUnreachableCodeWarnings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let f = fn() { return 1; }; f()", nil},
+		{"let f = fn() {\n  return 1;\n  let x = 2;\n  x\n};", []string{
+			"unreachable code after return (line 3, column 3)",
+		}},
+		{"for (x in a) { if (x) { continue; put(x) } break; x }", []string{
+			"unreachable code after continue (line 1, column 35)",
+			"unreachable code after break (line 1, column 51)",
+		}},
+		{"return 1; 2", []string{"unreachable code after return (line 1, column 11)"}},
+		{"if (x) { return 1; } 2", nil},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		checkParserError(t, p)
+
+		warnings := p.Warnings()
+		if len(warnings) != len(tt.expected) {
+			t.Errorf("wrong warnings for %q, expected: %v, got: %v", tt.input, tt.expected, warnings)
+			continue
+		}
+		for i, warning := range warnings {
+			if warning != tt.expected[i] {
+				t.Errorf("wrong warning for %q, expected: %q, got: %q", tt.input, tt.expected[i], warning)
+			}
+		}
+	}
+}
+
+func TestAssignExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x = 5", "x = 5"},
+		{"x = y = 1 + 2", "x = y = (1 + 2)"},
+		{"x = a == b", "x = (a == b)"},
+		{"f(a = 1)", "f(a = 1)"},
+		{"a[0] = 5", "(a[0]) = 5"},
+		{`grid[i][j] = v + 1`, "((grid[i])[j]) = (v + 1)"},
+		{`a[0] = b["k"] = 1`, "(a[0]) = (b[k]) = 1"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	p := New(lexer.New("1 = 2"))
+	p.ParseProgram()
+	errors := p.Errors()
+	if len(errors) == 0 || errors[0] != "cannot assign to 1" {
+		t.Errorf("unexpected parser errors: %v", errors)
+	}
+}
+
+func TestUnterminatedRawString(t *testing.T) {
+	l := lexer.New("let x = `abc")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	expected := "illegal token: unterminated raw string (line 1, column 9)"
+	if len(errors) != 1 || errors[0] != expected {
+		t.Fatalf("unexpected parser errors: %v", errors)
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedParam      string
+		expectedErrorParam string
+	}{
+		{`try { x } catch (e) { e }`, "e", ""},
+		{`try { x } catch { 1 }`, "", ""},
+		{`try { x } catch (e, err) { err }`, "e", "err"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		stm := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stm.Expression.(*ast.TryExpression)
+		if !ok {
+			t.Fatalf("stm.Expression is not TryExpression, got: %T", stm.Expression)
+		}
+
+		if len(exp.Block.Statements) != 1 || len(exp.Handler.Statements) != 1 {
+			t.Errorf("unexpected block sizes, got: %d and %d",
+				len(exp.Block.Statements), len(exp.Handler.Statements))
+		}
+
+		if tt.expectedParam == "" {
+			if exp.Param != nil {
+				t.Errorf("exp.Param is not nil, got: %s", exp.Param)
+			}
+			continue
+		}
+		testIdentifier(t, exp.Param, tt.expectedParam)
+
+		if tt.expectedErrorParam == "" {
+			if exp.ErrorParam != nil {
+				t.Errorf("exp.ErrorParam is not nil, got: %s", exp.ErrorParam)
+			}
+			continue
+		}
+		testIdentifier(t, exp.ErrorParam, tt.expectedErrorParam)
+	}
+}
+
+func TestSpreadExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(...args)", "f(...args)"},
+		{"f(1, ...rest, g(...x))", "f(1, ...rest, g(...x))"},
+		{"[1, ...rest, 5]", "[1, ...rest, 5]"},
+		{"[...a[0]]", "[...(a[0])]"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("Expected: %q, got: %q", tt.expected, actual)
+		}
+	}
+}
+
+func TestErrorsInSourceOrder(t *testing.T) {
+	input := `f(a = 1, g(2, ]))
+let = 5;`
+
+	l := lexer.New(input)
+	p := New(l)
+	p.ParseProgram()
+
+	expected := []string{
+		"positional argument follows keyword argument",
+		"no prefix parse function for ] found",
+		"Expect token to be ident, got = instead",
+	}
+
+	errors := p.Errors()
+	if len(errors) < len(expected) {
+		t.Fatalf("too few errors, expected at least: %v, got: %v", expected, errors)
+	}
+	for i, msg := range expected {
+		if errors[i] != msg {
+			t.Fatalf("errors not in source order, expected: %v, got: %v", expected, errors)
+		}
+	}
+}
+
+func TestFloatLiteralExpression(t *testing.T) {
+	l := lexer.New("3.25;")
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserError(t, p)
+
+	stm := program.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stm.Expression.(*ast.FloatLiteral)
+	if !ok {
+		t.Fatalf("stm.Expression not FloatLiteral, got %T", stm.Expression)
+	}
+
+	if literal.Value != 3.25 {
+		t.Fatalf("literal.Value not 3.25, got %f", literal.Value)
+	}
+}
+
+func TestDeferStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn() { defer close(f); read(f) }", "fn() defer close(f);read(f)"},
+		{"fn() { if (x) { defer x = 1 } }", "fn() ifx defer x = 1;"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	for _, input := range []string{"defer close(f);", "if (x) { defer y }"} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != "defer outside function" {
+			t.Errorf("unexpected parser errors for %q, got: %v", input, errors)
+		}
+	}
+}
+
+func TestMacroLiteralParsing(t *testing.T) {
+	p := New(lexer.New(`macro(x, y) { x + y; }`))
+	program := p.ParseProgram()
+	checkParserError(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement, got: %d", len(program.Statements))
+	}
+	stm, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("statement is not ast.ExpressionStatement, got: %T", program.Statements[0])
+	}
+	macro, ok := stm.Expression.(*ast.MacroLiteral)
+	if !ok {
+		t.Fatalf("stm.Expression is not ast.MacroLiteral, got: %T", stm.Expression)
+	}
+
+	if len(macro.Parameters) != 2 || macro.Parameters[0].Value != "x" || macro.Parameters[1].Value != "y" {
+		t.Errorf("macro.Parameters wrong, got: %v", macro.Parameters)
+	}
+	if macro.String() != "macro(x, y) (x + y)" {
+		t.Errorf("macro.String() wrong, got: %q", macro.String())
+	}
+}
+
+func TestASTEqual(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"1 + 2 * 3", "1+(2*3)", true},
+		{"1 + 2 * 3", "(1 + 2) * 3", false},
+		{"let x = 1;", "\n\n   let x=1", true},
+		{"let x = 1;", "let y = 1;", false},
+		{"let [a, b] = f();", "let [a,b]=f()", true},
+		{"let a, b = 1, 2;", "let a, b = 1, 3;", false},
+		{"a, b = b, a;", "a,b=b,a", true},
+		{"global g = 1.5", "global g = 1.5", true},
+		{"global g = 1.5", "global g = 1.25", false},
+		{`"a" + "b"`, `"a" + "c"`, false},
+		{"-x; !y", "-x; !y", true},
+		{"-x", "!x", false},
+		{"x++", "x--", false},
+		{"x = null", "x = null", true},
+		{"a[0] = true", "a[0] = false", false},
+		{"if (a) { 1 } else if (b) { 2 } else { 3 }", "if(a){1}else if(b){2}else{3}", true},
+		{"if (a) { 1 } else if (b) { 2 }", "if (a) { 1 } else if (c) { 2 }", false},
+		{"if (a) { 1 }", "if (a) { 1 } else { 2 }", false},
+		{"fn(x, y) { return x; }", "fn(x,y){return x}", true},
+		{"fn(x, y) { x }", "fn(y, x) { x }", false},
+		{"macro(x) { quote(x) }", "macro(x) { quote(x) }", true},
+		{"macro(x) { x }", "fn(x) { x }", false},
+		{"f(1, ...xs, k = 2)", "f(1, ...xs, k = 2)", true},
+		{"f(1, k = 2)", "f(1, j = 2)", false},
+		{"[1, [2]]", "[1, [2]]", true},
+		{"[1, [2]]", "[1, 2]", false},
+		{`{"a": 1, 2: b}`, `{"a":1,2:b}`, true},
+		{`{"a": 1, 2: b}`, `{2: b, "a": 1}`, false},
+		{"p.x.y", "p.x.y", true},
+		{"p.x", "p.y", false},
+		{"repeat { x } until (y)", "repeat { x } until (y)", true},
+		{"do { x } while (y)", "repeat { x } until (y)", false},
+		{"for (k, v in h) { v }", "for (k,v in h) {v}", true},
+		{"for (v in h) { v }", "for (k, v in h) { v }", false},
+		{"try { x } catch (e) { e }", "try { x } catch (e) { e }", true},
+		{"try { x } catch (e) { 1 }", "try { x } catch { 1 }", false},
+		{"try { x } catch (e, err) { 1 }", "try { x } catch (e) { 1 }", false},
+		{"for (x in xs) { break; continue; }", "for (x in xs) { break; continue; }", true},
+		{"for (x in xs) { break; }", "for (x in xs) { continue; }", false},
+		{"fn() { defer close(f) }", "fn() { defer close(f); }", true},
+		{"fn() { defer close(f) }", "fn() { close(f) }", false},
+		{"struct P { x, y fn n() { self.x } }", "struct P { x, y, fn n() { self.x } }", true},
+		{"struct P { x, y }", "struct P { y, x }", false},
+	}
+
+	for _, tt := range tests {
+		pa, pb := New(lexer.New(tt.a)), New(lexer.New(tt.b))
+		a, b := pa.ParseProgram(), pb.ParseProgram()
+		checkParserError(t, pa)
+		checkParserError(t, pb)
+
+		if got := ast.Equal(a, b); got != tt.equal {
+			t.Errorf("ast.Equal(%q, %q) wrong, expected: %t, got: %t", tt.a, tt.b, tt.equal, got)
+		}
+		if got := ast.Equal(b, a); got != tt.equal {
+			t.Errorf("ast.Equal(%q, %q) wrong, expected: %t, got: %t", tt.b, tt.a, tt.equal, got)
+		}
+	}
+}
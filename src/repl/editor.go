@@ -0,0 +1,184 @@
+package repl
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"monkey/src/evaluator"
+	"monkey/src/object"
+)
+
+// Control characters the line editor reacts to.
+const (
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyTab       = '\t'
+	keyEnter     = '\r'
+	keyNewline   = '\n'
+	keyEscape    = 0x1b
+	keyBackspace = 0x7f
+	keyCtrlH     = 0x08
+)
+
+// lineEditor reads a line from a terminal in raw mode, echoing it itself so it
+// can highlight the input and complete names on Tab. Only appending and
+// deleting at the end of the line are supported.
+type lineEditor struct {
+	in    io.Reader
+	out   io.Writer
+	color bool
+	// complete returns the candidates for the word before the cursor
+	complete func(prefix string) []string
+}
+
+// readLine returns the next line without its line break. It returns io.EOF
+// for Ctrl-D on an empty line and when in is exhausted before a line break.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	var line []rune
+	e.render(prompt, line)
+
+	for {
+		r, err := e.readRune()
+		if err != nil {
+			if len(line) > 0 && err == io.EOF {
+				io.WriteString(e.out, "\r\n")
+				return string(line), nil
+			}
+			return "", err
+		}
+
+		switch r {
+		case keyEnter, keyNewline:
+			io.WriteString(e.out, "\r\n")
+			return string(line), nil
+		case keyCtrlC:
+			// drop the line and start over, like a shell does
+			io.WriteString(e.out, "^C\r\n")
+			line = line[:0]
+		case keyCtrlD:
+			if len(line) == 0 {
+				io.WriteString(e.out, "\r\n")
+				return "", io.EOF
+			}
+		case keyBackspace, keyCtrlH:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		case keyTab:
+			line = e.completeWord(prompt, line)
+		case keyEscape:
+			// cursor movement is not supported, skip the whole sequence
+			e.skipEscapeSequence()
+		default:
+			if unicode.IsPrint(r) {
+				line = append(line, r)
+			}
+		}
+
+		e.render(prompt, line)
+	}
+}
+
+func (e *lineEditor) render(prompt string, line []rune) {
+	text := string(line)
+	if e.color {
+		text = Highlight(text)
+	}
+	io.WriteString(e.out, "\r"+prompt+text+"\x1b[K")
+}
+
+// completeWord extends the identifier before the cursor by the prefix all
+// candidates share. If that adds nothing and there is a choice, the
+// candidates are listed below the line.
+func (e *lineEditor) completeWord(prompt string, line []rune) []rune {
+	start := len(line)
+	for start > 0 && isIdentRune(line[start-1]) {
+		start--
+	}
+	word := string(line[start:])
+	if word == "" {
+		return line
+	}
+
+	candidates := e.complete(word)
+	if len(candidates) == 0 {
+		io.WriteString(e.out, "\a")
+		return line
+	}
+
+	common := commonPrefix(candidates)
+	if len(common) > len(word) {
+		return append(line, []rune(common[len(word):])...)
+	}
+	if len(candidates) > 1 {
+		io.WriteString(e.out, "\r\n"+strings.Join(candidates, "  ")+"\r\n")
+	}
+	return line
+}
+
+func (e *lineEditor) readRune() (rune, error) {
+	var buf [utf8.UTFMax]byte
+	if _, err := io.ReadFull(e.in, buf[:1]); err != nil {
+		return 0, err
+	}
+
+	n := 1
+	for n < len(buf) && !utf8.FullRune(buf[:n]) {
+		if _, err := io.ReadFull(e.in, buf[n:n+1]); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	r, _ := utf8.DecodeRune(buf[:n])
+	return r, nil
+}
+
+// skipEscapeSequence consumes the rest of a CSI sequence such as an arrow
+// key, which ends with a byte in the range '@' to '~'.
+func (e *lineEditor) skipEscapeSequence() {
+	r, err := e.readRune()
+	if err != nil || r != '[' {
+		return
+	}
+	for {
+		r, err := e.readRune()
+		if err != nil || (r >= '@' && r <= '~') {
+			return
+		}
+	}
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	return prefix
+}
+
+// completions returns the sorted names bound in env and the builtins that
+// start with prefix. Bindings shadow builtins of the same name, so each name
+// is listed once.
+func completions(env *object.Environment, prefix string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range append(env.Keys(), evaluator.BuiltinNames()...) {
+		if strings.HasPrefix(name, prefix) && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,94 @@
+package repl
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"monkey/src/object"
+)
+
+func TestCompletions(t *testing.T) {
+	env := object.NewEnvironment()
+	env.Set("length", &object.Integer{Value: 1})
+	env.Set("len", &object.Integer{Value: 2})
+	env.Set("other", &object.Integer{Value: 3})
+
+	tests := []struct {
+		prefix   string
+		expected string
+	}{
+		{"le", "len length"},
+		{"oth", "other"},
+		{"pu", "push put"},
+		{"zzz", ""},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(completions(env, tt.prefix), " "); got != tt.expected {
+			t.Errorf("completions(%q) wrong. expected=%q, got=%q", tt.prefix, tt.expected, got)
+		}
+	}
+}
+
+func TestLineEditor(t *testing.T) {
+	complete := func(prefix string) []string {
+		var names []string
+		for _, name := range []string{"push", "put", "counter"} {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + 2\r", "1 + 2"},
+		{"co\t + 1\r", "counter + 1"},
+		{"p\ts\t(1)\r", "push(1)"},
+		{"puts\x7f\x7fsh\r", "push"},
+		{"a\x1b[Db\r", "ab"},
+		{"gone\x03kept\r", "kept"},
+		{"héllo\r", "héllo"},
+		{"no newline", "no newline"},
+	}
+
+	for _, tt := range tests {
+		e := &lineEditor{in: strings.NewReader(tt.input), out: io.Discard, complete: complete}
+		line, err := e.readLine(PROMPT)
+		if err != nil {
+			t.Errorf("readLine(%q) returned error: %s", tt.input, err)
+			continue
+		}
+		if line != tt.expected {
+			t.Errorf("readLine(%q) wrong. expected=%q, got=%q", tt.input, tt.expected, line)
+		}
+	}
+}
+
+func TestLineEditorListsCandidates(t *testing.T) {
+	var out bytes.Buffer
+	e := &lineEditor{in: strings.NewReader("pu\t\r"), out: &out, complete: func(string) []string {
+		return []string{"push", "put"}
+	}}
+
+	if _, err := e.readLine(PROMPT); err != nil {
+		t.Fatalf("readLine returned error: %s", err)
+	}
+	if !strings.Contains(out.String(), "\r\npush  put\r\n") {
+		t.Errorf("candidates not listed, output: %q", out.String())
+	}
+}
+
+func TestLineEditorEOF(t *testing.T) {
+	for _, input := range []string{"", "\x04"} {
+		e := &lineEditor{in: strings.NewReader(input), out: io.Discard}
+		if _, err := e.readLine(PROMPT); err != io.EOF {
+			t.Errorf("readLine(%q) expected io.EOF, got: %v", input, err)
+		}
+	}
+}
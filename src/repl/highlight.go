@@ -0,0 +1,95 @@
+package repl
+
+import (
+	"os"
+	"strings"
+
+	"monkey/src/lexer"
+	"monkey/src/token"
+)
+
+// ANSI escape sequences used to colorize the session.
+const (
+	colorReset   = "\x1b[0m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorMagenta = "\x1b[35m"
+)
+
+// NoColor turns off syntax highlighting and colored errors. It defaults to
+// the NO_COLOR convention; colors are also left out whenever the session is
+// not attached to a terminal.
+var NoColor = os.Getenv("NO_COLOR") != ""
+
+func isTerminal(stream any) bool {
+	f, ok := stream.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// paint wraps text in color when enabled is set.
+func paint(text, color string, enabled bool) string {
+	if !enabled {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// Highlight returns line with keywords, strings, numbers and illegal input
+// wrapped in ANSI colors. Everything between the tokens, like whitespace, is
+// kept as typed.
+func Highlight(line string) string {
+	runes := []rune(line)
+	l := lexer.New(line)
+
+	var out strings.Builder
+	pos := 0
+	tok := l.NextToken()
+	for tok.Type != token.EOF {
+		next := l.NextToken()
+
+		// the lexer has no end positions, a token runs up to the next one
+		// minus the whitespace in between
+		start := tokenOffset(runes, tok)
+		end := len(runes)
+		if next.Type != token.EOF {
+			end = tokenOffset(runes, next)
+		}
+		text := strings.TrimRight(string(runes[start:end]), " \t\r\n")
+
+		out.WriteString(string(runes[pos:start]))
+		color := tokenColor(tok)
+		out.WriteString(paint(text, color, color != ""))
+		pos = start + len([]rune(text))
+
+		tok = next
+	}
+	out.WriteString(string(runes[pos:]))
+
+	return out.String()
+}
+
+// tokenOffset is the index into runes where tok starts. Columns are 1-based
+// and the highlighted input is a single line.
+func tokenOffset(runes []rune, tok token.Token) int {
+	return min(max(tok.Column-1, 0), len(runes))
+}
+
+func tokenColor(tok token.Token) string {
+	switch {
+	case tok.Type == token.STRING:
+		return colorGreen
+	case tok.Type == token.INT || tok.Type == token.FLOAT:
+		return colorYellow
+	case tok.Type == token.ILLEGAL:
+		return colorRed
+	case tok.Type != token.IDENT && token.LookUpIdent(tok.Literal) == tok.Type:
+		return colorMagenta
+	default:
+		return ""
+	}
+}
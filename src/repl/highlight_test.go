@@ -0,0 +1,36 @@
+package repl
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`let x = 5;`,
+			colorMagenta + "let" + colorReset + " x = " + colorYellow + "5" + colorReset + ";",
+		},
+		{
+			`  puts("a b",  1.5) `,
+			`  puts(` + colorGreen + `"a b"` + colorReset + `,  ` + colorYellow + "1.5" + colorReset + ") ",
+		},
+		{
+			`if (true) { null } else { "é" }`,
+			colorMagenta + "if" + colorReset + " (" + colorMagenta + "true" + colorReset + ") { " +
+				colorMagenta + "null" + colorReset + " } " + colorMagenta + "else" + colorReset + " { " +
+				colorGreen + `"é"` + colorReset + " }",
+		},
+		{
+			`a ? b`,
+			"a " + colorRed + "?" + colorReset + " b",
+		},
+		{``, ``},
+	}
+
+	for _, tt := range tests {
+		if got := Highlight(tt.input); got != tt.expected {
+			t.Errorf("Highlight(%q) wrong.\nexpected=%q\ngot=     %q", tt.input, tt.expected, got)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
 	"monkey/src/evaluator"
 	"monkey/src/lexer"
@@ -17,37 +19,148 @@ import (
 
 const PROMPT = ">>> "
 
+// ShowTypes prints the type after every result, as in `=> 5 : INTEGER`.
+// Turn it off for the bare value.
+var ShowTypes = true
+
+// Results are shown with at most this many elements per collection and this
+// much nesting, so big values do not flood the session.
+const (
+	maxInspectLength = 100
+	maxInspectDepth  = 10
+)
+
+// maxUndo is the number of lines `:undo` can take back.
+const maxUndo = 100
+
+// Start runs a session in a fresh environment with system access, since the
+// person typing at the prompt is trusted with the host.
 func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
 	env := object.NewEnvironment()
+	evaluator.SetOptions(env, evaluator.Options{AllowSystem: true})
 
-	for {
-		fmt.Fprint(out, PROMPT)
-		scanned := scanner.Scan()
+	StartWithEnv(env, in, out)
+}
+
+// StartWithEnv runs a session in env, so embedders can seed it with their own
+// bindings and evaluator options. Lines are evaluated until in is exhausted or
+// the program calls `exit`. Typing `:undo` restores the bindings of env to
+// what they were before the previous line.
+func StartWithEnv(env *object.Environment, in io.Reader, out io.Writer) {
+	// scripts calling readLine share the reader, so they get the lines
+	// typed after the current one
+	reader := bufio.NewReader(in)
+	evaluator.Input = reader
+	evaluator.Output = out
+
+	terminal := isTerminal(in) && isTerminal(out)
+	color := terminal && !NoColor
+
+	var editor *lineEditor
+	if terminal {
+		editor = &lineEditor{in: in, out: out, color: color, complete: func(prefix string) []string {
+			return completions(env, prefix)
+		}}
+	}
 
-		if !scanned {
+	// macros live apart from the values of the session, they only exist
+	// while lines are expanded
+	macroEnv := object.NewEnvironment()
+	var history []object.Snapshot
+	for {
+		line, err := readInput(reader, editor, out, color)
+		if err != nil {
 			return
 		}
-		line := scanner.Text()
+
+		if strings.TrimSpace(line) == ":undo" {
+			if len(history) == 0 {
+				io.WriteString(out, "nothing to undo\n")
+				continue
+			}
+			env.Restore(history[len(history)-1])
+			history = history[:len(history)-1]
+			continue
+		}
+
 		l := lexer.New(line)
 		p := parser.New(l)
 		program := p.ParseProgram()
 
 		if len(p.Errors()) != 0 {
-			printParserError(out, p.Errors())
+			printParserError(out, p.Errors(), color)
+			continue
+		}
+		printParserWarnings(out, p.Warnings(), color)
+
+		evaluator.DefineMacros(program, macroEnv)
+		expanded, failed := evaluator.ExpandMacros(program, macroEnv)
+		if failed != nil {
+			io.WriteString(out, paint(failed.Inspect(), colorRed, color))
+			io.WriteString(out, "\n")
 			continue
 		}
 
-		evaluated := evaluator.Eval(program, env)
-		if evaluated != nil {
-			io.WriteString(out, evaluated.Inspect())
+		history = append(history, env.Snapshot())
+		if len(history) > maxUndo {
+			history = history[1:]
+		}
+
+		evaluated := evaluator.Eval(expanded, env)
+		if _, ok := evaluated.(*object.Exit); ok {
+			return
+		}
+		if evaluated != nil && evaluated.Type() == object.ERROR_OBJ {
+			io.WriteString(out, paint(evaluated.Inspect(), colorRed, color))
+			io.WriteString(out, "\n")
+		} else if evaluated != nil {
+			io.WriteString(out, formatResult(evaluated))
 			io.WriteString(out, "\n")
 		}
 	}
 }
 
-func printParserError(out io.Writer, errors []string) {
+func formatResult(obj object.Object) string {
+	text := object.InspectLimited(obj, maxInspectLength, maxInspectDepth)
+	if !ShowTypes {
+		return text
+	}
+	return "=> " + text + " : " + string(obj.Type())
+}
+
+// readInput prompts for the next line. On a terminal the line editor reads
+// it, unless raw mode cannot be enabled; then the line is read as typed and
+// redrawn highlighted afterwards.
+func readInput(reader *bufio.Reader, editor *lineEditor, out io.Writer, color bool) (string, error) {
+	if editor != nil {
+		if restore, err := makeRaw(editor.in.(*os.File)); err == nil {
+			defer restore()
+			return editor.readLine(PROMPT)
+		}
+	}
+
+	fmt.Fprint(out, PROMPT)
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+
+	if color {
+		// the terminal already echoed the line, redraw it highlighted
+		fmt.Fprint(out, "\x1b[1A\r"+PROMPT+Highlight(line)+"\x1b[K\n")
+	}
+	return line, nil
+}
+
+func printParserError(out io.Writer, errors []string, color bool) {
 	for _, msg := range errors {
-		io.WriteString(out, "\t"+msg+"\n")
+		io.WriteString(out, "\t"+paint(msg, colorRed, color)+"\n")
+	}
+}
+
+func printParserWarnings(out io.Writer, warnings []string, color bool) {
+	for _, msg := range warnings {
+		io.WriteString(out, "\t"+paint("warning: "+msg, colorYellow, color)+"\n")
 	}
 }
@@ -0,0 +1,97 @@
+package repl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"monkey/src/object"
+)
+
+func TestStartWithEnv(t *testing.T) {
+	env := object.NewEnvironment()
+	env.Set("answer", &object.Integer{Value: 42})
+
+	var out bytes.Buffer
+	StartWithEnv(env, strings.NewReader("answer + 1\nlet x = answer\n"), &out)
+
+	expected := PROMPT + "=> 43 : INTEGER\n" + PROMPT + PROMPT
+	if out.String() != expected {
+		t.Errorf("wrong output. expected=%q, got=%q", expected, out.String())
+	}
+	if x, ok := env.Get("x"); !ok || x.Inspect() != "42" {
+		t.Errorf("binding made in the session not visible in env, got: %v", x)
+	}
+}
+
+func TestResultTypes(t *testing.T) {
+	defer func(show bool) { ShowTypes = show }(ShowTypes)
+
+	tests := []struct {
+		showTypes bool
+		input     string
+		expected  string
+	}{
+		{true, "5", "=> 5 : INTEGER"},
+		{true, "[1, 2]", "=> [1, 2] : ARRAY"},
+		{true, `"a"`, "=> a : STRING"},
+		{true, "null", "=> null : NULL"},
+		{true, "1 + true", "ERROR: type missmatch: INTEGER + BOOLEAN"},
+		{false, "[1, 2]", "[1, 2]"},
+	}
+
+	for _, tt := range tests {
+		ShowTypes = tt.showTypes
+
+		var out bytes.Buffer
+		Start(strings.NewReader(tt.input+"\n"), &out)
+
+		expected := PROMPT + tt.expected + "\n" + PROMPT
+		if out.String() != expected {
+			t.Errorf("wrong output for %q. expected=%q, got=%q", tt.input, expected, out.String())
+		}
+	}
+}
+
+func TestRunPrintsWarnings(t *testing.T) {
+	var out bytes.Buffer
+	code := Run("let f = fn() { return 1; 2 }; f()", nil, &out)
+
+	expected := "\twarning: unreachable code after return (line 1, column 26)\n"
+	if code != 0 || out.String() != expected {
+		t.Errorf("wrong result. expected=0 %q, got=%d %q", expected, code, out.String())
+	}
+}
+
+func TestUndo(t *testing.T) {
+	defer func(show bool) { ShowTypes = show }(ShowTypes)
+	ShowTypes = false
+
+	env := object.NewEnvironment()
+	input := ":undo\nlet x = 1\nlet x = 2\nlet y = 3\n:undo\n:undo\nx\ny\n"
+
+	var out bytes.Buffer
+	StartWithEnv(env, strings.NewReader(input), &out)
+
+	expected := PROMPT + "nothing to undo\n" + strings.Repeat(PROMPT, 6) + "1\n" +
+		PROMPT + "ERROR: identifier not found: `y`\n" + PROMPT
+	if out.String() != expected {
+		t.Errorf("wrong output. expected=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestMacrosAcrossLines(t *testing.T) {
+	defer func(show bool) { ShowTypes = show }(ShowTypes)
+	ShowTypes = false
+
+	input := "let twice = macro(x) { quote(unquote(x) * 2) }\ntwice(21)\ntwice(1, 2)\n"
+
+	var out bytes.Buffer
+	StartWithEnv(object.NewEnvironment(), strings.NewReader(input), &out)
+
+	expected := PROMPT + PROMPT + "42\n" + PROMPT +
+		"ERROR: wrong number of arguments to macro `twice`. got=2, want=1\n" + PROMPT
+	if out.String() != expected {
+		t.Errorf("wrong output. expected=%q, got=%q", expected, out.String())
+	}
+}
@@ -0,0 +1,55 @@
+package repl
+
+import (
+	"io"
+
+	"monkey/src/evaluator"
+	"monkey/src/lexer"
+	"monkey/src/object"
+	"monkey/src/parser"
+)
+
+// Run evaluates a whole script and returns the exit code for the process:
+// 0 on success, 1 when it does not parse or fails with an error, or the code
+// the script passed to `exit`. The script sees args as the array of strings
+// bound to ARGV. Parser warnings are written to out before it runs.
+func Run(source string, args []string, out io.Writer) int {
+	l := lexer.New(source)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		printParserError(out, p.Errors(), false)
+		return 1
+	}
+	printParserWarnings(out, p.Warnings(), false)
+
+	macroEnv := object.NewEnvironment()
+	evaluator.DefineMacros(program, macroEnv)
+	expanded, failed := evaluator.ExpandMacros(program, macroEnv)
+	if failed != nil {
+		io.WriteString(out, failed.Inspect())
+		io.WriteString(out, "\n")
+		return 1
+	}
+
+	env := object.NewEnvironment()
+	evaluator.SetOptions(env, evaluator.Options{AllowSystem: true})
+
+	argv := make([]object.Object, len(args))
+	for i, arg := range args {
+		argv[i] = &object.String{Value: arg}
+	}
+	env.Set("ARGV", &object.Array{Elements: argv})
+
+	switch evaluated := evaluator.Eval(expanded, env).(type) {
+	case *object.Exit:
+		return int(evaluated.Code)
+	case *object.Error:
+		io.WriteString(out, evaluated.Inspect())
+		io.WriteString(out, "\n")
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package repl
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw switches the terminal f to raw input, so keys arrive one by one and
+// are not echoed, and Ctrl-C is read as a key instead of raising a signal.
+// The returned function restores the previous mode.
+func makeRaw(f *os.File) (func(), error) {
+	fd := f.Fd()
+
+	var old syscall.Termios
+	if err := ioctl(fd, syscall.TCGETS, &old); err != nil {
+		return nil, err
+	}
+
+	raw := old
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.ISIG
+	raw.Iflag &^= syscall.ICRNL | syscall.IXON
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctl(fd, syscall.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() { ioctl(fd, syscall.TCSETS, &old) }, nil
+}
+
+func ioctl(fd, request uintptr, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
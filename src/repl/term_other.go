@@ -0,0 +1,14 @@
+//go:build !linux
+
+package repl
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw is only implemented for Linux, elsewhere the REPL reads whole
+// lines without completion.
+func makeRaw(f *os.File) (func(), error) {
+	return nil, errors.ErrUnsupported
+}
@@ -20,20 +20,38 @@ package token
 // Let's just take our token's type as a string for now
 type TokenType string
 
-// Each token will have a type, and their respective literal
+// Each token will have a type, and their respective literal.
+// Line and Column locate the first character of the token, both start at 1
+// and the column is counted in runes rather than bytes.
 type Token struct {
 	Type    TokenType
 	Literal string
+	Line    int
+	Column  int
 }
 
 var keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
-	"true":   TRUE,
-	"false":  FALSE,
+	"fn":       FUNCTION,
+	"let":      LET,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"true":     TRUE,
+	"false":    FALSE,
+	"in":       IN,
+	"global":   GLOBAL,
+	"try":      TRY,
+	"catch":    CATCH,
+	"null":     NULL,
+	"struct":   STRUCT,
+	"until":    UNTIL,
+	"do":       DO,
+	"while":    WHILE,
+	"for":      FOR,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"defer":    DEFER,
+	"macro":    MACRO,
 }
 
 func LookUpIdent(ident string) TokenType {
@@ -51,21 +69,27 @@ const (
 	// Identifier
 	IDENT = "ident"
 	INT   = "INT"
+	FLOAT = "FLOAT"
 
 	// Operators
-	ASSIGN   = "="
-	PLUS     = "+"
-	MINUS    = "-"
-	BANG     = "!"
-	ASTERISK = "*"
-	SLASH    = "/"
-	NOT_EQ   = "!="
-	EQ       = "=="
+	ASSIGN    = "="
+	PLUS      = "+"
+	MINUS     = "-"
+	BANG      = "!"
+	ASTERISK  = "*"
+	SLASH     = "/"
+	PERCENT   = "%"
+	INCREMENT = "++"
+	DECREMENT = "--"
+	NOT_EQ    = "!="
+	EQ        = "=="
 
 	// Delimiter
 	COMMA     = ","
 	SEMICOLON = ";"
 	COLON     = ":"
+	ELLIPSIS  = "..."
+	DOT       = "."
 
 	LPAREN   = "("
 	RPAREN   = ")"
@@ -84,6 +108,20 @@ const (
 	RETURN   = "RETURN"
 	IF       = "IF"
 	ELSE     = "ELSE"
+	IN       = "IN"
+	GLOBAL   = "GLOBAL"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	NULL     = "NULL"
+	STRUCT   = "STRUCT"
+	UNTIL    = "UNTIL"
+	DO       = "DO"
+	WHILE    = "WHILE"
+	FOR      = "FOR"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+	DEFER    = "DEFER"
+	MACRO    = "MACRO"
 
 	STRING = "STRING"
 )
@@ -0,0 +1,301 @@
+package typecheck
+
+import (
+	"fmt"
+	"strconv"
+
+	"monkey/src/ast"
+	"monkey/src/object"
+	"monkey/src/token"
+)
+
+// Diagnostic is a definite type error found without running the program.
+type Diagnostic struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s", d.Line, d.Column, d.Message)
+}
+
+// unknown is the type of expressions whose type depends on runtime values,
+// such as identifiers and calls. They are never reported.
+const unknown object.ObjectType = ""
+
+type checker struct {
+	diagnostics []Diagnostic
+}
+
+// Check walks program and reports expressions that would always fail to
+// evaluate, like `5 + true` or calling an integer. It is conservative: only
+// literals and expressions built from them have a known type, so anything
+// that depends on a binding or a call is assumed to be fine.
+func Check(program *ast.Program) []Diagnostic {
+	c := &checker{}
+	for _, stm := range program.Statements {
+		c.statement(stm)
+	}
+	return c.diagnostics
+}
+
+func (c *checker) report(tok token.Token, format string, a ...interface{}) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (c *checker) statement(stm ast.Statement) {
+	switch stm := stm.(type) {
+	case *ast.LetStatement:
+		c.expression(stm.Value)
+	case *ast.DestructuringStatement:
+		if valueType := c.expression(stm.Value); valueType != unknown && valueType != object.ARRAY_OBJ {
+			c.report(stm.Token, "cannot destructure %s, expected ARRAY", valueType)
+		}
+	case *ast.ParallelLetStatement:
+		for _, value := range stm.Values {
+			c.expression(value)
+		}
+	case *ast.ParallelAssignStatement:
+		for _, value := range stm.Values {
+			c.expression(value)
+		}
+	case *ast.GlobalStatement:
+		c.expression(stm.Value)
+	case *ast.StructStatement:
+		for _, method := range stm.Methods {
+			c.block(method.Function.Body)
+		}
+	case *ast.ReturnStatement:
+		c.expression(stm.ReturnValue)
+	case *ast.DeferStatement:
+		c.expression(stm.Expression)
+	case *ast.ExpressionStatement:
+		c.expression(stm.Expression)
+	case *ast.BlockStatement:
+		c.block(stm)
+	}
+}
+
+func (c *checker) block(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+	for _, stm := range block.Statements {
+		c.statement(stm)
+	}
+}
+
+// expression checks exp and its children and returns the type exp is known
+// to evaluate to, or unknown.
+func (c *checker) expression(exp ast.Expression) object.ObjectType {
+	switch exp := exp.(type) {
+	case *ast.IntegerLiteral:
+		return object.INTEGER_OBJ
+	case *ast.FloatLiteral:
+		return object.FLOAT_OBJ
+	case *ast.Boolean:
+		return object.BOOLEAN_OBJ
+	case *ast.StringLiteral:
+		return object.STRING_OBJ
+	case *ast.NullLiteral:
+		return object.NULL_OBJ
+	case *ast.ArrayLiteral:
+		for _, el := range exp.Elements {
+			c.expression(el)
+		}
+		return object.ARRAY_OBJ
+	case *ast.HashLiteral:
+		seen := make(map[string]bool)
+		for _, key := range exp.Keys {
+			if literal, ok := literalKey(key); ok {
+				if seen[literal] {
+					c.report(exp.Token, "duplicate hash key: %s", literal)
+				}
+				seen[literal] = true
+			}
+			keyType := c.expression(key)
+			if keyType != unknown && !isHashable(keyType) {
+				c.report(exp.Token, "unuseable as a hashkey: %s", keyType)
+			}
+			c.expression(exp.Pairs[key])
+		}
+		return object.HASH_OBJ
+	case *ast.FunctionLiteral:
+		c.block(exp.Body)
+		return object.FUNCTION_OBJ
+	case *ast.MacroLiteral:
+		// calling a macro is not a function call, so its type stays open
+		c.block(exp.Body)
+		return unknown
+	case *ast.PrefixExpression:
+		return c.prefix(exp)
+	case *ast.InfixExpression:
+		return c.infix(exp)
+	case *ast.AssignExpression:
+		return c.expression(exp.Value)
+	case *ast.IndexAssignExpression:
+		leftType := c.expression(exp.Target.Left)
+		c.expression(exp.Target.Index)
+		if leftType != unknown && leftType != object.ARRAY_OBJ && leftType != object.HASH_OBJ {
+			c.report(exp.Token, "index assignment not supported: %s", leftType)
+		}
+		return c.expression(exp.Value)
+	case *ast.IfExpression:
+		c.expression(exp.Condition)
+		c.block(exp.Consequence)
+		for _, branch := range exp.ElseIfs {
+			c.expression(branch.Condition)
+			c.block(branch.Consequence)
+		}
+		c.block(exp.Alternative)
+	case *ast.RepeatExpression:
+		c.block(exp.Body)
+		c.expression(exp.Condition)
+	case *ast.ForInExpression:
+		iterableType := c.expression(exp.Iterable)
+		if iterableType != unknown && iterableType != object.ARRAY_OBJ && iterableType != object.HASH_OBJ {
+			c.report(exp.Token, "cannot iterate over %s", iterableType)
+		}
+		c.block(exp.Body)
+	case *ast.DoWhileExpression:
+		c.block(exp.Body)
+		c.expression(exp.Condition)
+	case *ast.TryExpression:
+		c.block(exp.Block)
+		c.block(exp.Handler)
+	case *ast.CallExpression:
+		fnType := c.expression(exp.Function)
+		if fnType != unknown && fnType != object.FUNCTION_OBJ {
+			c.report(exp.Token, "not a function: %s", fnType)
+		}
+		for _, arg := range exp.Arguments {
+			c.expression(arg)
+		}
+	case *ast.KeywordArgument:
+		c.expression(exp.Value)
+	case *ast.SpreadExpression:
+		valueType := c.expression(exp.Value)
+		if valueType != unknown && valueType != object.ARRAY_OBJ {
+			c.report(exp.Token, "cannot spread %s, expected ARRAY", valueType)
+		}
+	case *ast.MemberExpression:
+		if objectType := c.expression(exp.Object); objectType != unknown && objectType != object.HASH_OBJ {
+			c.report(exp.Token, "member access not supported: %s", objectType)
+		}
+	case *ast.IndexExpression:
+		leftType := c.expression(exp.Left)
+		indexType := c.expression(exp.Index)
+		switch {
+		case leftType == unknown:
+		case leftType == object.HASH_OBJ:
+			if indexType != unknown && !isHashable(indexType) {
+				c.report(exp.Token, "unusable as hash key: %s", indexType)
+			}
+		case leftType != object.ARRAY_OBJ:
+			c.report(exp.Token, "index operator not supported: %s", leftType)
+		}
+	}
+
+	return unknown
+}
+
+func (c *checker) prefix(exp *ast.PrefixExpression) object.ObjectType {
+	right := c.expression(exp.Right)
+
+	switch exp.Operator {
+	case "!":
+		return object.BOOLEAN_OBJ
+	case "-":
+		if right == unknown {
+			return unknown
+		}
+		if !isNumber(right) {
+			c.report(exp.Token, "unknown operation: -%s", right)
+			return unknown
+		}
+		return right
+	}
+
+	return unknown
+}
+
+// infix mirrors the rules of the evaluator for operands of known types.
+func (c *checker) infix(exp *ast.InfixExpression) object.ObjectType {
+	left := c.expression(exp.Left)
+	right := c.expression(exp.Right)
+	op := exp.Operator
+
+	// an unknown operand may be a hash overloading the comparisons too
+	if left == unknown || right == unknown {
+		if op == "in" {
+			return object.BOOLEAN_OBJ
+		}
+		return unknown
+	}
+	// a hash can overload every operator but `in` with a method, whose
+	// result is not known here
+	if left == object.HASH_OBJ && op != "in" {
+		return unknown
+	}
+
+	switch {
+	case op == "in":
+		if right != object.HASH_OBJ && right != object.ARRAY_OBJ && right != object.SET_OBJ &&
+			!(right == object.STRING_OBJ && left == object.STRING_OBJ) {
+			c.report(exp.Token, "type missmatch: %s in %s", left, right)
+		}
+		return object.BOOLEAN_OBJ
+	case op == "%" && left == object.STRING_OBJ:
+		return object.STRING_OBJ
+	case isNumber(left) && isNumber(right):
+		switch op {
+		case "+", "-", "*", "/", "%":
+			if left == object.FLOAT_OBJ || right == object.FLOAT_OBJ {
+				return object.FLOAT_OBJ
+			}
+			return object.INTEGER_OBJ
+		case "<", ">", "==", "!=":
+			return object.BOOLEAN_OBJ
+		}
+	case (left == object.NULL_OBJ || right == object.NULL_OBJ) && (op == "==" || op == "!="):
+		return object.BOOLEAN_OBJ
+	case left != right:
+		c.report(exp.Token, "type missmatch: %s %s %s", left, op, right)
+		return unknown
+	case left == object.STRING_OBJ && op == "+":
+		return object.STRING_OBJ
+	case left != object.STRING_OBJ && (op == "==" || op == "!="):
+		return object.BOOLEAN_OBJ
+	}
+
+	c.report(exp.Token, "unknown operation: %s %s %s", left, op, right)
+	return unknown
+}
+
+func isNumber(t object.ObjectType) bool {
+	return t == object.INTEGER_OBJ || t == object.FLOAT_OBJ
+}
+
+func isHashable(t object.ObjectType) bool {
+	return t == object.INTEGER_OBJ || t == object.BOOLEAN_OBJ || t == object.STRING_OBJ
+}
+
+// literalKey renders a hash key written as a literal the way the evaluator
+// reports duplicates of it.
+func literalKey(key ast.Expression) (string, bool) {
+	switch key := key.(type) {
+	case *ast.StringLiteral:
+		return strconv.Quote(key.Value), true
+	case *ast.IntegerLiteral:
+		return strconv.FormatInt(key.Value, 10), true
+	case *ast.Boolean:
+		return strconv.FormatBool(key.Value), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,73 @@
+package typecheck
+
+import (
+	"testing"
+
+	"monkey/src/lexer"
+	"monkey/src/parser"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"5 + 5 * 2", nil},
+		{"let x = 5; x + true", nil},
+		{"5 + true", []string{"1:3: type missmatch: INTEGER + BOOLEAN"}},
+		{"-true", []string{"1:1: unknown operation: -BOOLEAN"}},
+		{"true + false", []string{"1:6: unknown operation: BOOLEAN + BOOLEAN"}},
+		{`"a" - "b"`, []string{"1:5: unknown operation: STRING - STRING"}},
+		{`"a" + "b" == "ab"`, []string{"1:11: unknown operation: STRING == STRING"}},
+		{"5(1)", []string{"1:2: not a function: INTEGER"}},
+		{`"abc"[0]`, []string{"1:6: index operator not supported: STRING"}},
+		{`{"a": 1}[[1]]`, []string{"1:9: unusable as hash key: ARRAY"}},
+		{"1 in 2", []string{"1:3: type missmatch: INTEGER in INTEGER"}},
+		{"null == 1", nil},
+		{"1.5 * 2 - 1", nil},
+		{"-1.5 + true", []string{"1:6: type missmatch: FLOAT + BOOLEAN"}},
+		{`"%d" % [1]`, nil},
+		{"[...1]", []string{"1:2: cannot spread INTEGER, expected ARRAY"}},
+		{"let [a, b] = 5;", []string{"1:1: cannot destructure INTEGER, expected ARRAY"}},
+		{"let [a, b] = [1, 2];", nil},
+		{`"abc"[0] = "x"`, []string{"1:10: index assignment not supported: STRING"}},
+		{"for (x in 1) { x }", []string{"1:1: cannot iterate over INTEGER"}},
+		{`{"a": 1, "b": 2, "a": 3}`, []string{`1:1: duplicate hash key: "a"`}},
+		{`{1: 1, "1": 2, true: 3, x: 4, x: 5}`, nil},
+		{"[1].x", []string{"1:4: member access not supported: ARRAY"}},
+		{"struct P { x fn f() { 1 + true } }", []string{"1:25: type missmatch: INTEGER + BOOLEAN"}},
+		{"(1 + 2) + (3 > 2)", []string{"1:9: type missmatch: INTEGER + BOOLEAN"}},
+		{`{"__add__": fn(o) { 1 }} + {}`, nil},
+		{`let h = {"__lt__": fn(o) { 5 }}; (h < 1) + 1`, nil},
+		{`({} - 1) + true`, nil},
+		{`{} in 1`, []string{"1:4: type missmatch: HASH in INTEGER"}},
+		{
+			"let f = fn(x) {\n  if (x) { 1 + true } else { -\"a\" }\n};",
+			[]string{
+				"2:14: type missmatch: INTEGER + BOOLEAN",
+				"2:30: unknown operation: -STRING",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("parser errors for %q: %v", tt.input, p.Errors())
+		}
+
+		diagnostics := Check(program)
+		if len(diagnostics) != len(tt.expected) {
+			t.Errorf("wrong number of diagnostics for %q, expected: %v, got: %v", tt.input, tt.expected, diagnostics)
+			continue
+		}
+
+		for i, d := range diagnostics {
+			if d.String() != tt.expected[i] {
+				t.Errorf("diagnostic wrong for %q, expected: %s, got: %s", tt.input, tt.expected[i], d.String())
+			}
+		}
+	}
+}